@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var AddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a ready feature to the project (e.g. middleware, auth)",
+}
+
+func init() {
+	AddCmd.AddCommand(AddMiddlewareCmd)
+	AddCmd.AddCommand(AddAuthCmd)
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"log"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// authMountPath is where add auth mounts the auth endpoints.
+const authMountPath = "/auth"
+
+var AddAuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Add JWT authentication: register, login, refresh and me endpoints",
+	Long: "Generate the auth repository, usecase (bcrypt password hashing), token service, request DTOs and handler, " +
+		"wire them in bootstrap.go and mount register, login, refresh and me under " + authMountPath + ". " +
+		"The me endpoint is guarded by middlewares.JWT, which puts the token claims in the request context.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		moduleName, err := getModuleName()
+		if err != nil {
+			fatalf("❌ Failed to get module name from go.mod: %v", err)
+		}
+		n := mustParseName("auth")
+		data := newComponentData(moduleName, n)
+		cfg := projectConfig()
+		log.Println("🚀 Generating auth")
+
+		files := []struct {
+			kind, name, path, tmpl string
+			keep                   bool // generated only when missing
+		}{
+			{"model", "user", filepath.Join(cfg.Layers.Models.Dir, fileName(cfg.Files.Model, "user")), "models.tmpl", true},
+			{"middleware", "jwt", filepath.Join(cfg.Layers.Middlewares.Dir, "jwt.go"), "middleware_jwt.tmpl", true},
+			{"service", "token", filepath.Join(cfg.Layers.Services.Dir, "token.go"), "auth_token_service.tmpl", false},
+			{"repository", n.Snake, filepath.Join(cfg.Layers.Repositories.Dir, n.Dir, fileName(cfg.Files.Interface, n.Snake)), "auth_repository_interface.tmpl", false},
+			{"repository", n.Snake, filepath.Join(cfg.Layers.Repositories.Dir, n.Dir, fileName(cfg.Files.Repository, n.Snake)), "auth_repository.tmpl", false},
+			{"usecase", n.Snake, filepath.Join(cfg.Layers.Usecases.Dir, n.Dir, fileName(cfg.Files.Interface, n.Snake)), "auth_usecase_interface.tmpl", false},
+			{"usecase", n.Snake, filepath.Join(cfg.Layers.Usecases.Dir, n.Dir, fileName(cfg.Files.Usecase, n.Snake)), "auth_usecase.tmpl", false},
+			{"handler", n.Snake, filepath.Join(cfg.Layers.Request.Dir, fileName(cfg.Files.Request, n.Snake)), "auth_request.tmpl", false},
+			{"handler", n.Snake, filepath.Join(cfg.Layers.Handlers.Dir, fileName(cfg.Files.Handler, n.Snake)), "auth_handler.tmpl", false},
+		}
+		ensureValidation(data)
+		ensureAppErrors(data)
+		for _, f := range files {
+			if f.keep && fileExists(f.path) {
+				continue
+			}
+			written, err := generateFile(f.kind, f.name, f.path, f.tmpl, data)
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			if written {
+				fmt.Println("✅ Created:", f.path)
+			}
+		}
+		if err := writeModelRegistry(); err != nil {
+			log.Printf("❌ Failed to update %s: %v", modelRegistryPath(), err)
+		}
+
+		if err := createOrUpdateRepositoriesIndex(n.Pascal, n.Dir); err != nil {
+			fatalf("❌ Failed to create/update repositories.go: %v", err)
+		}
+		if err := createOrUpdateUsecasesIndex(n.Pascal, n.Dir); err != nil {
+			fatalf("❌ Failed to create/update usecases.go: %v", err)
+		}
+		if err := requireModules("github.com/golang-jwt/jwt/v5", "golang.org/x/crypto"); err != nil {
+			fatalf("❌ Failed to update go.mod: %v", err)
+		}
+
+		bootstrapPath := bootstrapFilePath()
+		if !fileExists(bootstrapPath) {
+			if _, err := renderTemplate(bootstrapPath, "bootstrap.tmpl", newTemplateData(moduleName)); err != nil {
+				fatalf("❌ Failed to create bootstrap.go: %v", err)
+			}
+			log.Println("📦 Created new bootstrap.go")
+		}
+		if err := injectAuthToBootstrap(); err != nil {
+			log.Printf("❌ Failed to inject to bootstrap: %v", err)
+		} else {
+			log.Println("✅ Injected to bootstrap.go")
+		}
+
+		if err := injectRoute(n.Pascal, routeGroup(), authMountPath); err != nil {
+			log.Printf("❌ Failed to register routes: %v", err)
+		} else {
+			log.Println("✅ Routes registered in router.go")
+		}
+		if err := allowPublicPath(path.Join("/", routeGroup(), authMountPath) + "/*"); err != nil {
+			log.Printf("❌ Failed to make the auth endpoints public: %v", err)
+		}
+
+		if src, err := readFile(filepath.Join(cfg.Layers.Config.Dir, "config.go")); err == nil && !strings.Contains(string(src), "RefreshSecret") {
+			fmt.Println("⚠️  config.JWTConfig has no RefreshSecret, AccessTTL and RefreshTTL yet, add them from JWT_REFRESH_SECRET, JWT_ACCESS_TTL and JWT_REFRESH_TTL")
+		}
+		fmt.Println("ℹ️  Set JWT_SECRET and JWT_REFRESH_SECRET to two different random values")
+	},
+}
+
+// injectAuthToBootstrap wires the auth repository, token service, usecase
+// and handler into bootstrap.go. The handler guards its me endpoint with
+// middlewares.JWT.
+func injectAuthToBootstrap() error {
+	module, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
+
+	b, err := parseBootstrap()
+	if err != nil {
+		return err
+	}
+
+	layers := projectConfig().templateLayers(module)
+	repoPkg := b.ensureImport(layers.Repositories.Import, layers.Repositories.Package)
+	servicesPkg := b.ensureImport(layers.Services.Import, layers.Services.Package)
+	usecasePkg := b.ensureImport(layers.Usecases.Import, layers.Usecases.Package)
+	handlerPkg := b.ensureImport(layers.Handlers.Import, layers.Handlers.Package)
+	mwPkg := b.ensureImport(layers.Middlewares.Import, layers.Middlewares.Package)
+	db, logger, cfg := b.db(), b.logger(), b.config()
+
+	tokens := "tokenService, err := " + servicesPkg + ".NewTokenService(" + cfg + ".JWT)\n" +
+		"\tif err != nil {\n" +
+		"\t\t" + logger + `.Error("failed to create the token service", "error", err)` + "\n" +
+		"\t\t" + b.ensureImport("os", "os") + ".Exit(1)\n" +
+		"\t}"
+	if b.findComment(b.fn.Body, "Services") == nil && lastConstructorCall(b.fn, servicesPkg, "Service") == nil {
+		tokens = "\n\t// Services\n\t" + tokens
+	}
+	authenticate := mwPkg + ".JWT(" + mwPkg + ".JWTOptions{Secret: []byte(" + cfg + ".JWT.Secret)})"
+
+	b.injectWiring([]bootstrapWiring{
+		{"authRepo", repoPkg, "Repositories", "authRepo := " + repoPkg + ".NewAuthRepository(" + db + ", " + logger + ")", "Repository"},
+		{"tokenService", servicesPkg, "Services", tokens, "Service"},
+		{"authUsecase", usecasePkg, "Usecases", "authUsecase := " + usecasePkg + ".NewAuthUsecase(authRepo, tokenService, " + logger + ")", "Usecase"},
+		{"authHandler", handlerPkg, "Handlers", "authHandler := " + handlerPkg + ".NewAuthHandler(authUsecase, " + authenticate + ", " + logger + ")", "Handler"},
+	})
+	b.injectDependency("AuthHandler", "*"+handlerPkg+".AuthHandler", "authHandler")
+
+	out, err := b.bytes()
+	if err != nil {
+		return err
+	}
+	return writeTracked(b.path, out)
+}
+
+// mountedPublicPaths lists the public paths of the endpoints InitRouter
+// already mounts: the auth endpoints of add auth and the docs of openapi
+// --docs. A JWT middleware registered after them must let them through.
+func mountedPublicPaths() ([]string, error) {
+	module, err := getModuleName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module name: %w", err)
+	}
+	layers := projectConfig().templateLayers(module)
+
+	routerPath := routerFilePath()
+	src, err := readFile(routerPath)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return nil, err
+	}
+	fn := s.findFunc("InitRouter")
+	if fn == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return nil, nil
+	}
+	deps := fn.Type.Params.List[0].Names[0].Name
+	docsPkg := s.importName(layers.Docs.Import, layers.Docs.Package)
+
+	var auth, docs bool
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			auth = auth || x.Name == deps && sel.Sel.Name == "AuthHandler"
+			docs = docs || docsPkg != "" && x.Name == docsPkg && sel.Sel.Name == "Handler"
+		}
+		return true
+	})
+
+	var paths []string
+	if auth {
+		paths = append(paths, path.Join("/", routeGroup(), authMountPath)+"/*")
+	}
+	if docs {
+		paths = append(paths, docsMountPath+"*")
+	}
+	return paths, nil
+}
+
+// allowPublicPath adds pattern to the public paths of the middlewares.JWT
+// registered in InitRouter, if any, so that signing in needs no token.
+func allowPublicPath(pattern string) error {
+	module, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
+	layers := projectConfig().templateLayers(module)
+
+	routerPath := routerFilePath()
+	src, err := readFile(routerPath)
+	if err != nil {
+		return err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return err
+	}
+	mwPkg := s.importName(layers.Middlewares.Import, layers.Middlewares.Package)
+	fn := s.findFunc("InitRouter")
+	if mwPkg == "" || fn == nil {
+		return nil
+	}
+
+	var public *ast.CompositeLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || public != nil {
+			return public == nil
+		}
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "JWTOptions" {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != mwPkg {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Public" {
+					public, _ = kv.Value.(*ast.CompositeLit)
+				}
+			}
+		}
+		return false
+	})
+	if public == nil {
+		return nil
+	}
+
+	quoted := strconv.Quote(pattern)
+	for _, elt := range public.Elts {
+		if lit, ok := elt.(*ast.BasicLit); ok && lit.Value == quoted {
+			return nil
+		}
+	}
+	if len(public.Elts) == 0 {
+		s.insert(public.Lbrace+1, quoted)
+	} else {
+		s.insert(public.Elts[len(public.Elts)-1].End(), ", "+quoted)
+	}
+
+	out, err := s.bytes()
+	if err != nil {
+		return err
+	}
+	return writeTracked(routerPath, out)
+}
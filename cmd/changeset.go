@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// DryRun makes every generator record its writes in the change set instead of
+// touching the disk. It is bound to the global --dry-run flag.
+var DryRun bool
+
+// plannedFile is a pending write. before is nil when the file does not exist
+// yet, after is nil when the file is removed.
+type plannedFile struct {
+	before []byte
+	after  []byte
+}
+
+// changeSet collects every file a command plans to create, modify or remove, in order.
+type changeSet struct {
+	order []string
+	files map[string]*plannedFile
+}
+
+var changes = &changeSet{files: make(map[string]*plannedFile)}
+
+// writeFile writes data to path, or records the write when running dry.
+func writeFile(path string, data []byte) error {
+	if !DryRun {
+		return os.WriteFile(path, data, 0644)
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return changes.plan(path, data)
+}
+
+// removeFile deletes path, or records the removal when running dry.
+func removeFile(path string) error {
+	if !DryRun {
+		return os.Remove(path)
+	}
+	return changes.plan(path, nil)
+}
+
+// plan records that path will hold data, or be removed when data is nil.
+func (c *changeSet) plan(path string, data []byte) error {
+	path = filepath.ToSlash(filepath.Clean(path))
+	if planned, ok := c.files[path]; ok {
+		planned.after = data
+		return nil
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	c.order = append(c.order, path)
+	c.files[path] = &plannedFile{before: before, after: data}
+	return nil
+}
+
+// readFile reads path, seeing writes already planned by this run.
+func readFile(path string) ([]byte, error) {
+	if DryRun {
+		if planned, ok := changes.files[filepath.ToSlash(filepath.Clean(path))]; ok {
+			if planned.after == nil {
+				return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+			}
+			return planned.after, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// fileExists reports whether path exists on disk or is planned to.
+func fileExists(path string) bool {
+	if DryRun {
+		if planned, ok := changes.files[filepath.ToSlash(filepath.Clean(path))]; ok {
+			return planned.after != nil
+		}
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// listFiles returns the names of the files in dir, seeing the writes and
+// removals already planned by this run. A missing dir holds no files.
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && fileExists(filepath.Join(dir, entry.Name())) {
+			names = append(names, entry.Name())
+		}
+	}
+	if DryRun {
+		for _, path := range changes.order {
+			name := filepath.Base(path)
+			if filepath.Dir(path) == filepath.ToSlash(filepath.Clean(dir)) && changes.files[path].after != nil && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// makeDir creates a directory tree unless running dry.
+func makeDir(path string) error {
+	if DryRun {
+		return nil
+	}
+	return os.MkdirAll(path, os.ModePerm)
+}
+
+// removeEmptyDirs removes dir and its parents up to, but not including, stop
+// as long as they are empty. It does nothing when running dry.
+func removeEmptyDirs(dir, stop string) {
+	if DryRun {
+		return
+	}
+	stop = filepath.Clean(stop)
+	for dir = filepath.Clean(dir); dir != stop && dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// PrintChanges writes the recorded change set to w as a unified diff.
+func PrintChanges(w io.Writer) {
+	if !DryRun {
+		return
+	}
+
+	fmt.Fprintln(w, "")
+	if len(changes.order) == 0 {
+		fmt.Fprintln(w, "🔍 Dry run: nothing would change.")
+		return
+	}
+
+	created, modified, removed := 0, 0, 0
+	for _, path := range changes.order {
+		planned := changes.files[path]
+		diff := unifiedDiff(path, planned.before, planned.after)
+		if diff == "" {
+			continue
+		}
+		switch {
+		case planned.before == nil:
+			created++
+		case planned.after == nil:
+			removed++
+		default:
+			modified++
+		}
+		fmt.Fprint(w, diff)
+	}
+
+	fmt.Fprintf(w, "\n🔍 Dry run: %d file(s) would be created, %d modified, %d removed. Nothing was written.\n", created, modified, removed)
+}
+
+// Finish records what a command did: it saves the manifest and prints the
+// change set of a dry run. It runs after failed commands too.
+func Finish(w io.Writer) {
+	SaveManifest()
+	PrintChanges(w)
+}
+
+// fatalf reports a failure like log.Fatalf, once the files the command already
+// wrote are recorded in the manifest, or shown when running dry.
+func fatalf(format string, v ...any) {
+	Finish(os.Stdout)
+	log.Fatalf(format, v...)
+}
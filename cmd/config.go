@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigPath is the project configuration written by init and read by
+// every command.
+const projectConfigPath = ".gostart.yaml"
+
+// LayerConfig sets where a layer lives and the package name it declares.
+type LayerConfig struct {
+	Dir     string `yaml:"dir"`
+	Package string `yaml:"package"`
+}
+
+// ProjectConfig is the content of .gostart.yaml. Empty values fall back to
+// the defaults from defaultProjectConfig.
+type ProjectConfig struct {
+	Module string `yaml:"module,omitempty"`
+
+	Layers struct {
+		Usecases     LayerConfig `yaml:"usecases"`
+		Repositories LayerConfig `yaml:"repositories"`
+		Handlers     LayerConfig `yaml:"handlers"`
+		Bootstrap    LayerConfig `yaml:"bootstrap"`
+		Routes       LayerConfig `yaml:"routes"`
+		Database     LayerConfig `yaml:"database"`
+		Models       LayerConfig `yaml:"models"`
+		Config       LayerConfig `yaml:"config"`
+		Request      LayerConfig `yaml:"request"`
+		Response     LayerConfig `yaml:"response"`
+		Middlewares  LayerConfig `yaml:"middlewares"`
+		Services     LayerConfig `yaml:"services"`
+		Logging      LayerConfig `yaml:"logging"`
+		Mocks        LayerConfig `yaml:"mocks"`
+		Pagination   LayerConfig `yaml:"pagination"`
+		Errors       LayerConfig `yaml:"errors"`
+		Docs         LayerConfig `yaml:"docs"`
+	} `yaml:"layers"`
+
+	// Files are file name patterns, {name} is replaced by the component name.
+	Files struct {
+		Usecase    string `yaml:"usecase"`
+		Repository string `yaml:"repository"`
+		Handler    string `yaml:"handler"`
+		Interface  string `yaml:"interface"`
+		Model      string `yaml:"model"`
+		Request    string `yaml:"request"`
+	} `yaml:"files"`
+
+	// Router is the HTTP router the handlers and InitRouter are generated for.
+	Router string `yaml:"router"`
+
+	// Database selects the driver of the generated db.go, env and docker files.
+	Database struct {
+		Driver string `yaml:"driver"`
+	} `yaml:"database"`
+
+	// Response selects the body of error responses: envelope, problem
+	// (RFC 7807) or jsonapi.
+	Response struct {
+		Errors string `yaml:"errors"`
+	} `yaml:"response"`
+
+	// Routes controls where create feature mounts handlers in InitRouter.
+	Routes struct {
+		Prefix    string `yaml:"prefix"`
+		Version   string `yaml:"version"`
+		Pluralize *bool  `yaml:"pluralize"`
+	} `yaml:"routes"`
+
+	// Defaults are applied to the global flags that are not set explicitly.
+	Defaults struct {
+		DryRun       bool `yaml:"dry_run"`
+		Force        bool `yaml:"force"`
+		SkipExisting bool `yaml:"skip_existing"`
+		Interactive  bool `yaml:"interactive"`
+	} `yaml:"defaults"`
+}
+
+func defaultProjectConfig() *ProjectConfig {
+	var c ProjectConfig
+	c.Layers.Usecases = LayerConfig{"internal/app/usecases", "usecases"}
+	c.Layers.Repositories = LayerConfig{"internal/infrastructure/repositories", "repositories"}
+	c.Layers.Handlers = LayerConfig{"internal/interface/handlers", "handler"}
+	c.Layers.Bootstrap = LayerConfig{"internal/app/bootstrap", "bootstrap"}
+	c.Layers.Routes = LayerConfig{"internal/interface/routes", "routes"}
+	c.Layers.Database = LayerConfig{"internal/infrastructure/databases", "database"}
+	c.Layers.Models = LayerConfig{"internal/infrastructure/databases/models", "models"}
+	c.Layers.Config = LayerConfig{"internal/app/config", "config"}
+	c.Layers.Request = LayerConfig{"internal/interface/request", "request"}
+	c.Layers.Response = LayerConfig{"internal/interface/response", "response"}
+	c.Layers.Middlewares = LayerConfig{"internal/infrastructure/middlewares", "middlewares"}
+	c.Layers.Services = LayerConfig{"internal/infrastructure/services", "services"}
+	c.Layers.Logging = LayerConfig{"internal/infrastructure/logging", "logging"}
+	c.Layers.Mocks = LayerConfig{"internal/mocks", "mocks"}
+	c.Layers.Pagination = LayerConfig{"internal/infrastructure/pagination", "pagination"}
+	c.Layers.Errors = LayerConfig{"internal/app/apperror", "apperror"}
+	c.Layers.Docs = LayerConfig{"internal/interface/docs", "docs"}
+	c.Router = "chi"
+	c.Database.Driver = "mysql"
+	c.Response.Errors = "envelope"
+	pluralize := true
+	c.Routes.Pluralize = &pluralize
+	c.Files.Usecase = "{name}_usecase.go"
+	c.Files.Repository = "{name}_repository.go"
+	c.Files.Handler = "{name}_handler.go"
+	c.Files.Interface = "interface.go"
+	c.Files.Model = "{name}.go"
+	c.Files.Request = "{name}_request.go"
+	return &c
+}
+
+var loadedConfig *ProjectConfig
+
+// projectConfig returns the project configuration, loading .gostart.yaml on
+// first use. A missing file means the default layout.
+func projectConfig() *ProjectConfig {
+	if loadedConfig != nil {
+		return loadedConfig
+	}
+
+	cfg := defaultProjectConfig()
+	content, err := os.ReadFile(projectConfigPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		fatalf("❌ Failed to read %s: %v", projectConfigPath, err)
+	default:
+		var fileCfg ProjectConfig
+		if err := yaml.Unmarshal(content, &fileCfg); err != nil {
+			fatalf("❌ Invalid %s: %v", projectConfigPath, err)
+		}
+		cfg.merge(&fileCfg)
+	}
+
+	loadedConfig = cfg
+	return cfg
+}
+
+// merge overrides c with every value set in other.
+func (c *ProjectConfig) merge(other *ProjectConfig) {
+	setString(&c.Module, other.Module)
+
+	layers := []struct{ dst, src *LayerConfig }{
+		{&c.Layers.Usecases, &other.Layers.Usecases},
+		{&c.Layers.Repositories, &other.Layers.Repositories},
+		{&c.Layers.Handlers, &other.Layers.Handlers},
+		{&c.Layers.Bootstrap, &other.Layers.Bootstrap},
+		{&c.Layers.Routes, &other.Layers.Routes},
+		{&c.Layers.Database, &other.Layers.Database},
+		{&c.Layers.Models, &other.Layers.Models},
+		{&c.Layers.Config, &other.Layers.Config},
+		{&c.Layers.Request, &other.Layers.Request},
+		{&c.Layers.Response, &other.Layers.Response},
+		{&c.Layers.Middlewares, &other.Layers.Middlewares},
+		{&c.Layers.Services, &other.Layers.Services},
+		{&c.Layers.Logging, &other.Layers.Logging},
+		{&c.Layers.Mocks, &other.Layers.Mocks},
+		{&c.Layers.Pagination, &other.Layers.Pagination},
+		{&c.Layers.Errors, &other.Layers.Errors},
+		{&c.Layers.Docs, &other.Layers.Docs},
+	}
+	for _, l := range layers {
+		setString(&l.dst.Dir, strings.Trim(path.Clean("/"+l.src.Dir), "/"))
+		setString(&l.dst.Package, l.src.Package)
+	}
+
+	setString(&c.Files.Usecase, other.Files.Usecase)
+	setString(&c.Files.Repository, other.Files.Repository)
+	setString(&c.Files.Handler, other.Files.Handler)
+	setString(&c.Files.Interface, other.Files.Interface)
+	setString(&c.Files.Model, other.Files.Model)
+	setString(&c.Files.Request, other.Files.Request)
+
+	setString(&c.Router, other.Router)
+	setString(&c.Database.Driver, other.Database.Driver)
+	setString(&c.Response.Errors, other.Response.Errors)
+	setString(&c.Routes.Prefix, other.Routes.Prefix)
+	setString(&c.Routes.Version, other.Routes.Version)
+	if other.Routes.Pluralize != nil {
+		c.Routes.Pluralize = other.Routes.Pluralize
+	}
+	c.Defaults = other.Defaults
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// fileName expands a file name pattern for the component name.
+func fileName(pattern, name string) string {
+	return strings.ReplaceAll(pattern, "{name}", name)
+}
+
+func (l LayerConfig) layer(module string) types.Layer {
+	return types.Layer{Dir: l.Dir, Import: module + "/" + l.Dir, Package: l.Package}
+}
+
+// templateLayers resolves every layer to its import path within module.
+func (c *ProjectConfig) templateLayers(module string) types.Layers {
+	return types.Layers{
+		Usecases:     c.Layers.Usecases.layer(module),
+		Repositories: c.Layers.Repositories.layer(module),
+		Handlers:     c.Layers.Handlers.layer(module),
+		Bootstrap:    c.Layers.Bootstrap.layer(module),
+		Routes:       c.Layers.Routes.layer(module),
+		Database:     c.Layers.Database.layer(module),
+		Models:       c.Layers.Models.layer(module),
+		Config:       c.Layers.Config.layer(module),
+		Request:      c.Layers.Request.layer(module),
+		Response:     c.Layers.Response.layer(module),
+		Middlewares:  c.Layers.Middlewares.layer(module),
+		Services:     c.Layers.Services.layer(module),
+		Logging:      c.Layers.Logging.layer(module),
+		Mocks:        c.Layers.Mocks.layer(module),
+		Pagination:   c.Layers.Pagination.layer(module),
+		Errors:       c.Layers.Errors.layer(module),
+		Docs:         c.Layers.Docs.layer(module),
+	}
+}
+
+// writeProjectConfig stores cfg as .gostart.yaml.
+func writeProjectConfig(cfg *ProjectConfig) (bool, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# gostart project configuration, read by every gostart command.\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return false, fmt.Errorf("failed to encode %s: %w", projectConfigPath, err)
+	}
+	return writeGenerated(projectConfigPath, buf.Bytes())
+}
+
+// ApplyConfigDefaults sets the global flags from the defaults section of
+// .gostart.yaml unless they were given on the command line.
+func ApplyConfigDefaults(c *cobra.Command) {
+	defaults := projectConfig().Defaults
+
+	if !c.Flags().Changed("dry-run") {
+		DryRun = defaults.DryRun
+	}
+
+	// The conflict flags are mutually exclusive, so any of them on the
+	// command line replaces the configured choice entirely.
+	for _, name := range []string{"force", "skip-existing", "interactive"} {
+		if c.Flags().Changed(name) {
+			return
+		}
+	}
+	Force = defaults.Force
+	SkipExisting = defaults.SkipExisting
+	Interactive = defaults.Interactive
+}
+
+// newTemplateData returns template data carrying the module and the
+// configured layers; callers fill in the component names.
+func newTemplateData(moduleName string) types.TemplateData {
+	cfg := projectConfig()
+	db, err := lookupDatabase(cfg.Database.Driver)
+	if err != nil {
+		fatalf("❌ Invalid %s: %v", projectConfigPath, err)
+	}
+	if _, err := lookupRouter(cfg.Router); err != nil {
+		fatalf("❌ Invalid %s: %v", projectConfigPath, err)
+	}
+	if err := lookupErrorFormat(cfg.Response.Errors); err != nil {
+		fatalf("❌ Invalid %s: %v", projectConfigPath, err)
+	}
+	return types.TemplateData{
+		ModuleName:  moduleName,
+		Layers:      cfg.templateLayers(moduleName),
+		Database:    db,
+		Router:      cfg.Router,
+		ErrorFormat: cfg.Response.Errors,
+	}
+}
+
+// nestedLayer is the sub-package of l a component with parent directories
+// lives in, e.g. internal/interface/handlers/shop for shop/order_item.
+func nestedLayer(l types.Layer, parents []string) types.Layer {
+	if len(parents) == 0 {
+		return l
+	}
+	dir := path.Join(parents...)
+	return types.Layer{
+		Dir:     path.Join(l.Dir, dir),
+		Import:  l.Import + "/" + dir,
+		Package: parents[len(parents)-1],
+	}
+}
+
+// newComponentData returns template data for the component called n.
+func newComponentData(moduleName string, n types.Name) types.TemplateData {
+	data := newTemplateData(moduleName)
+	data.Name = n
+	data.ServiceName = n.Pascal
+	data.ServiceNameLower = n.Package
+	return data
+}
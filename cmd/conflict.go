@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Conflict handling for generated files, bound to the global flags.
+var (
+	Force        bool
+	SkipExisting bool
+	Interactive  bool
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// writeGenerated writes a freshly generated file. When a different file is
+// already there it refuses, unless --force, --skip-existing or --interactive
+// says otherwise; a file the manifest shows nobody edited, by hand or by an
+// injection, since gostart generated it is simply regenerated. It reports
+// whether the generated content was written.
+func writeGenerated(path string, data []byte) (bool, error) {
+	if Force {
+		return true, writeFile(path, data)
+	}
+	err := generatedConflict(path, data)
+	switch {
+	case err == nil:
+		return true, writeFile(path, data)
+	case SkipExisting:
+		fmt.Println("⏭️  Kept existing file:", path)
+		return false, nil
+	case Interactive:
+		current, readErr := readFile(path)
+		if readErr != nil {
+			return false, readErr
+		}
+		return resolveConflict(path, current, data)
+	}
+	return false, err
+}
+
+// generatedConflict returns why data cannot simply be written to path, or nil
+// when the file is missing, already holds data or is safe to regenerate.
+func generatedConflict(path string, data []byte) error {
+	if !fileExists(path) {
+		return nil
+	}
+	current, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+
+	m := loadManifest()
+	recorded := m.file(path)
+	switch {
+	case recorded != nil && !recorded.Injected && !m.edited(path):
+		// Regenerating a file nobody touched loses nothing
+		return nil
+	case recorded != nil && recorded.Injected && !m.edited(path):
+		return fmt.Errorf("%s holds code later gostart commands added (use --force to overwrite it, --skip-existing to keep it, or --interactive to decide per file)", path)
+	case recorded != nil:
+		return fmt.Errorf("%s was edited since gostart generated it (use --force to overwrite it, --skip-existing to keep it, or --interactive to decide per file)", path)
+	}
+	return fmt.Errorf("%s already exists (use --force to overwrite it, --skip-existing to keep it, or --interactive to decide per file)", path)
+}
+
+// checkOwner refuses to generate p for the component kind/name when the
+// manifest records it for other components only, such as the mock of
+// admin/user_role when generating user_role. Files of the project, such as
+// models/registry.go, are shared with every component regenerating them.
+// --force overrides it.
+func checkOwner(kind, name, p string) error {
+	if Force || !fileExists(p) {
+		return nil
+	}
+	owners := loadManifest().owners(p)
+	if len(owners) == 0 {
+		return nil
+	}
+	for _, c := range owners {
+		if c.Kind == "project" || c.Kind == kind && c.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s belongs to the %s %s (use --force to overwrite it)", p, owners[0].Kind, owners[0].Name)
+}
+
+// resolveConflict asks what to do with a conflicting file until it gets a
+// decision: keep it, overwrite it, show the diff, or merge with conflict markers.
+func resolveConflict(path string, current, generated []byte) (bool, error) {
+	for {
+		fmt.Printf("⚠️  %s already exists. [k]eep, [o]verwrite, [d]iff, [m]erge? ", path)
+		answer, err := stdin.ReadString('\n')
+		if err != nil && answer == "" {
+			return false, fmt.Errorf("no answer for %s: %w", path, err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "k", "keep":
+			fmt.Println("⏭️  Kept existing file:", path)
+			return false, nil
+		case "o", "overwrite":
+			return true, writeFile(path, generated)
+		case "d", "diff":
+			fmt.Print(unifiedDiff(path, current, generated))
+		case "m", "merge":
+			if err := writeFile(path, mergeConflict(current, generated)); err != nil {
+				return false, err
+			}
+			fmt.Println("🔀 Merged with conflict markers, resolve them in:", path)
+			return false, nil
+		}
+	}
+}
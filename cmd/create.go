@@ -12,4 +12,14 @@ func init() {
 	CreateCmd.AddCommand(UsecaseCmd)
 	CreateCmd.AddCommand(RepositoryCmd)
 	CreateCmd.AddCommand(FeatureCmd)
+	CreateCmd.AddCommand(ModelCmd)
+	CreateCmd.AddCommand(MiddlewareCmd)
+	CreateCmd.AddCommand(MigrationCmd)
+	CreateCmd.AddCommand(SeederCmd)
+
+	for _, c := range []*cobra.Command{HandlerCmd, UsecaseCmd, RepositoryCmd, FeatureCmd, ModelCmd} {
+		addFieldsFlag(c)
+	}
+	MigrationCmd.Flags().BoolVar(&fromModels, "from-models", false, "Draft the SQL from the models missing in the database (new tables, columns and indexes)")
+	FeatureCmd.Flags().StringVar(&routePath, "path", "", "Path to mount the feature routes at (default from .gostart.yaml routes, e.g. /orders)")
 }
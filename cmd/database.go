@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+)
+
+// databases lists the drivers init can generate a project for.
+var databases = map[string]types.Database{
+	"postgres": {
+		Driver:  "postgres",
+		Import:  "gorm.io/driver/postgres",
+		Package: "postgres",
+		Port:    "5432",
+		User:    "postgres",
+		Image:   "postgres:16-alpine",
+	},
+	"mysql": {
+		Driver:  "mysql",
+		Import:  "gorm.io/driver/mysql",
+		Package: "mysql",
+		Port:    "3306",
+		User:    "root",
+		Image:   "mysql:8.4",
+	},
+	"sqlite": {
+		Driver:  "sqlite",
+		Import:  "gorm.io/driver/sqlite",
+		Package: "sqlite",
+	},
+	"sqlserver": {
+		Driver:  "sqlserver",
+		Import:  "gorm.io/driver/sqlserver",
+		Package: "sqlserver",
+		Port:    "1433",
+		User:    "sa",
+		Image:   "mcr.microsoft.com/mssql/server:2022-latest",
+	},
+}
+
+// moduleVersions are the versions gostart requires in go.mod for the generated code.
+var moduleVersions = map[string]string{
+	"gorm.io/gorm":                 "v1.31.2",
+	"gorm.io/driver/postgres":      "v1.6.3",
+	"gorm.io/driver/mysql":         "v1.6.0",
+	"gorm.io/driver/sqlite":        "v1.6.0",
+	"gorm.io/driver/sqlserver":     "v1.6.3",
+	"github.com/joho/godotenv":     "v1.5.1",
+	"github.com/go-chi/chi/v5":     "v5.2.2",
+	"github.com/labstack/echo/v4":  "v4.13.4",
+	"github.com/gin-gonic/gin":     "v1.10.1",
+	"github.com/gofiber/fiber/v2":  "v2.52.9",
+	"github.com/golang-jwt/jwt/v5": "v5.3.0",
+	"golang.org/x/crypto":          "v0.43.0",
+}
+
+// lookupDatabase returns the driver called name.
+func lookupDatabase(name string) (types.Database, error) {
+	db, ok := databases[strings.ToLower(name)]
+	if !ok {
+		return db, fmt.Errorf("unknown database %q (use %s)", name, strings.Join(databaseNames(), ", "))
+	}
+	return db, nil
+}
+
+func databaseNames() []string {
+	names := make([]string, 0, len(databases))
+	for name := range databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+var DestroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Remove a generated resource (e.g. feature, handler, repository, usecase)",
+}
+
+var destroyFeatureCmd = &cobra.Command{
+	Use:   "feature [name]",
+	Short: "Remove the usecase, repository and handler of a feature and unwire them",
+	Long:  "Remove the usecase, repository and handler files of a feature, rebuild the usecases and repositories indexes, and remove the feature from bootstrap.go and router.go. The model is removed too when the manifest shows gostart generated it.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		log.Println("🧹 Destroying feature:", n.Raw)
+
+		handler, usecase, repository := handlerFiles(n), usecaseFiles(n), repositoryFiles(n)
+		seeder, model := componentFiles("seeder", n), componentFiles("model", n)
+		mustBeUnedited(slices.Concat(handler, usecase, repository, seeder, model)...)
+
+		destroyHandler(n, handler)
+		destroyUsecase(n, usecase)
+		destroyRepository(n, repository)
+
+		if err := removeFromBootstrap(n, "Repository", "Usecase", "Handler"); err != nil {
+			fatalf("❌ Failed to remove feature from bootstrap.go: %v", err)
+		}
+
+		// The seeder uses the removed repository
+		removeGenerated("seeder", n, seeder)
+
+		// Only a model the manifest knows about was generated with the feature
+		removeGenerated("model", n, model)
+		modelPath := filepath.Join(projectConfig().Layers.Models.Dir, fileName(projectConfig().Files.Model, n.Snake))
+		if fileExists(modelPath) {
+			fmt.Println("ℹ️  Kept model:", modelPath)
+		}
+		if err := writeModelRegistry(); err != nil {
+			log.Printf("❌ Failed to update %s: %v", modelRegistryPath(), err)
+		}
+	},
+}
+
+var destroyUsecaseCmd = &cobra.Command{
+	Use:   "usecase [name]",
+	Short: "Remove a usecase, its index entry and its bootstrap wiring",
+	Long:  "Remove a usecase, its index entry and its constructor call in bootstrap.go. Handlers still using it must be removed too, or use `destroy feature`.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		files := usecaseFiles(n)
+		mustBeUnedited(files...)
+		destroyUsecase(n, files)
+		if err := removeFromBootstrap(n, "Usecase"); err != nil {
+			fatalf("❌ Failed to remove usecase from bootstrap.go: %v", err)
+		}
+	},
+}
+
+var destroyRepositoryCmd = &cobra.Command{
+	Use:   "repository [name]",
+	Short: "Remove a repository, its index entry and its bootstrap wiring",
+	Long:  "Remove a repository, its index entry and its constructor call in bootstrap.go. Usecases still using it must be removed too, or use `destroy feature`.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		files := repositoryFiles(n)
+		mustBeUnedited(files...)
+		destroyRepository(n, files)
+		if err := removeFromBootstrap(n, "Repository"); err != nil {
+			fatalf("❌ Failed to remove repository from bootstrap.go: %v", err)
+		}
+	},
+}
+
+var destroyHandlerCmd = &cobra.Command{
+	Use:   "handler [name]",
+	Short: "Remove a handler, its request DTOs, its bootstrap wiring and its routes",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		files := handlerFiles(n)
+		mustBeUnedited(files...)
+		destroyHandler(n, files)
+		if err := removeFromBootstrap(n, "Handler"); err != nil {
+			fatalf("❌ Failed to remove handler from bootstrap.go: %v", err)
+		}
+	},
+}
+
+func usecaseFiles(n types.Name) []string {
+	cfg := projectConfig()
+	dir := filepath.Join(cfg.Layers.Usecases.Dir, filepath.FromSlash(n.Dir))
+	return componentFiles("usecase", n,
+		filepath.Join(dir, fileName(cfg.Files.Usecase, n.Snake)),
+		filepath.Join(dir, fileName(cfg.Files.Interface, n.Snake)),
+		filepath.Join(dir, testFileName(cfg.Files.Usecase, n.Snake)),
+		filepath.Join(cfg.Layers.Mocks.Dir, fileName(cfg.Files.Usecase, n.Snake)),
+	)
+}
+
+func destroyUsecase(n types.Name, files []string) {
+	cfg := projectConfig()
+	removeGenerated("usecase", n, files)
+	removeEmptyDirs(filepath.Join(cfg.Layers.Usecases.Dir, filepath.FromSlash(n.Dir)), cfg.Layers.Usecases.Dir)
+
+	if err := removeFromUsecasesIndex(n.Dir); err != nil {
+		fatalf("❌ Failed to update usecases.go: %v", err)
+	}
+}
+
+func repositoryFiles(n types.Name) []string {
+	cfg := projectConfig()
+	dir := filepath.Join(cfg.Layers.Repositories.Dir, filepath.FromSlash(n.Dir))
+	return componentFiles("repository", n,
+		filepath.Join(dir, fileName(cfg.Files.Repository, n.Snake)),
+		filepath.Join(dir, fileName(cfg.Files.Interface, n.Snake)),
+		filepath.Join(dir, testFileName(cfg.Files.Repository, n.Snake)),
+		filepath.Join(cfg.Layers.Mocks.Dir, fileName(cfg.Files.Repository, n.Snake)),
+	)
+}
+
+func destroyRepository(n types.Name, files []string) {
+	cfg := projectConfig()
+	removeGenerated("repository", n, files)
+	removeEmptyDirs(filepath.Join(cfg.Layers.Repositories.Dir, filepath.FromSlash(n.Dir)), cfg.Layers.Repositories.Dir)
+
+	if err := removeFromRepositoriesIndex(n.Dir); err != nil {
+		fatalf("❌ Failed to update repositories.go: %v", err)
+	}
+}
+
+func handlerFiles(n types.Name) []string {
+	cfg := projectConfig()
+	dir := filepath.FromSlash(nestedLayer(types.Layer{Dir: cfg.Layers.Handlers.Dir}, n.Parents).Dir)
+	return componentFiles("handler", n,
+		filepath.Join(dir, fileName(cfg.Files.Handler, n.Snake)),
+		filepath.Join(dir, testFileName(cfg.Files.Handler, n.Snake)),
+		filepath.Join(cfg.Layers.Request.Dir, fileName(cfg.Files.Request, n.Snake)),
+	)
+}
+
+func destroyHandler(n types.Name, files []string) {
+	cfg := projectConfig()
+	removeGenerated("handler", n, files)
+	removeEmptyDirs(filepath.FromSlash(nestedLayer(types.Layer{Dir: cfg.Layers.Handlers.Dir}, n.Parents).Dir), cfg.Layers.Handlers.Dir)
+
+	if err := removeRoute(n.Pascal); err != nil {
+		fatalf("❌ Failed to remove routes from router.go: %v", err)
+	}
+}
+
+// componentFiles returns the files of the component kind/name: the ones
+// recorded in the manifest when there are any, otherwise paths.
+func componentFiles(kind string, n types.Name, paths ...string) []string {
+	c := loadManifest().find(kind, componentName(n))
+	if c == nil {
+		return paths
+	}
+	var files []string
+	for _, f := range c.Files {
+		files = append(files, f.Path)
+	}
+	return files
+}
+
+// mustBeUnedited stops before anything is removed when one of paths was
+// edited since gostart generated it, unless --force is set. Removing the rest
+// of a component, such as its wiring, would break the kept file.
+func mustBeUnedited(paths ...string) {
+	if Force {
+		return
+	}
+	m := loadManifest()
+	var edited []string
+	for _, path := range paths {
+		if fileExists(path) && m.edited(path) {
+			edited = append(edited, path)
+		}
+	}
+	if len(edited) > 0 {
+		fatalf("❌ Edited since gostart generated them, nothing was removed (use --force to remove them anyway): %s", strings.Join(edited, ", "))
+	}
+}
+
+// removeGenerated deletes the files of the component kind/name, as returned
+// by componentFiles.
+func removeGenerated(kind string, n types.Name, paths []string) {
+	m := loadManifest()
+	name := componentName(n)
+	for _, path := range paths {
+		if !fileExists(path) {
+			m.forget(kind, name, path)
+			continue
+		}
+		if err := removeFile(path); err != nil {
+			fatalf("❌ Failed to remove %s: %v", path, err)
+		}
+		m.forget(kind, name, path)
+		fmt.Println("🗑️  Removed:", path)
+	}
+}
+
+func init() {
+	DestroyCmd.AddCommand(destroyFeatureCmd)
+	DestroyCmd.AddCommand(destroyUsecaseCmd)
+	DestroyCmd.AddCommand(destroyRepositoryCmd)
+	DestroyCmd.AddCommand(destroyHandlerCmd)
+}
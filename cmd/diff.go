@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one line of a line-based diff: ' ' kept, '-' removed, '+' added.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+const diffContext = 3
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a minimal line diff between a and b using the longest
+// common subsequence. Generated files are small, so the quadratic table is fine.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedDiff renders the change from before to after in unified diff format.
+// A nil before or after marks a created or deleted file.
+func unifiedDiff(path string, before, after []byte) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var buf strings.Builder
+	from, to := "a/"+path, "b/"+path
+	if before == nil {
+		from = "/dev/null"
+	}
+	if after == nil {
+		to = "/dev/null"
+	}
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", from, to)
+
+	// Line numbers (0-based) in a and b at the start of each op.
+	oldAt := make([]int, len(ops)+1)
+	newAt := make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldAt[k+1], newAt[k+1] = oldAt[k], newAt[k]
+		if op.kind != '+' {
+			oldAt[k+1]++
+		}
+		if op.kind != '-' {
+			newAt[k+1]++
+		}
+	}
+
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+
+		start := max(k-diffContext, 0)
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(ops))
+				break
+			}
+			end = run
+		}
+
+		oldLen := oldAt[end] - oldAt[start]
+		newLen := newAt[end] - newAt[start]
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(oldAt[start], oldLen), hunkRange(newAt[start], newLen))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		k = end
+	}
+
+	return buf.String()
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// mergeConflict combines the current and generated contents, keeping common
+// lines and wrapping every differing block in git-style conflict markers.
+func mergeConflict(current, generated []byte) []byte {
+	var buf strings.Builder
+	var ours, theirs []string
+
+	flush := func() {
+		if len(ours) == 0 && len(theirs) == 0 {
+			return
+		}
+		buf.WriteString("<<<<<<< current\n")
+		for _, l := range ours {
+			buf.WriteString(withNewline(l))
+		}
+		buf.WriteString("=======\n")
+		for _, l := range theirs {
+			buf.WriteString(withNewline(l))
+		}
+		buf.WriteString(">>>>>>> gostart\n")
+		ours, theirs = nil, nil
+	}
+
+	for _, op := range diffLines(splitLines(current), splitLines(generated)) {
+		switch op.kind {
+		case '-':
+			ours = append(ours, op.line)
+		case '+':
+			theirs = append(theirs, op.line)
+		default:
+			flush()
+			buf.WriteString(op.line)
+		}
+	}
+	flush()
+
+	return []byte(buf.String())
+}
+
+func withNewline(line string) string {
+	if strings.HasSuffix(line, "\n") {
+		return line
+	}
+	return line + "\n"
+}
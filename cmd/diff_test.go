@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after []byte
+		want          string
+	}{
+		{
+			name:   "unchanged",
+			before: []byte("a\nb\n"),
+			after:  []byte("a\nb\n"),
+			want:   "",
+		},
+		{
+			name:  "created",
+			after: []byte("a\nb\n"),
+			want:  "--- /dev/null\n+++ b/f.go\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:   "removed",
+			before: []byte("a\n"),
+			want:   "--- a/f.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:   "modified",
+			before: []byte("a\nb\nc\n"),
+			after:  []byte("a\nB\nc\nd\n"),
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -1,3 +1,4 @@\n a\n-b\n+B\n c\n+d\n",
+		},
+		{
+			name:   "no newline at end of file",
+			before: []byte("a\nb"),
+			after:  []byte("a\nb\n"),
+			want:   "--- a/f.go\n+++ b/f.go\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("f.go", tt.before, tt.after); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	// Changes more than twice the context apart get a hunk each
+	var before, after strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&before, "%d\n", i)
+		switch i {
+		case 2, 18:
+			fmt.Fprintf(&after, "changed %d\n", i)
+		default:
+			fmt.Fprintf(&after, "%d\n", i)
+		}
+	}
+
+	diff := unifiedDiff("f.go", []byte(before.String()), []byte(after.String()))
+	for _, header := range []string{"@@ -1,5 +1,5 @@\n", "@@ -15,6 +15,6 @@\n"} {
+		if !strings.Contains(diff, header) {
+			t.Errorf("diff lacks hunk %q:\n%s", header, diff)
+		}
+	}
+	if n := strings.Count(diff, "@@ -"); n != 2 {
+		t.Errorf("diff has %d hunks, want 2:\n%s", n, diff)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	tests := []struct {
+		name               string
+		current, generated string
+		want               string
+	}{
+		{
+			name:      "identical",
+			current:   "a\nb\n",
+			generated: "a\nb\n",
+			want:      "a\nb\n",
+		},
+		{
+			name:      "changed line",
+			current:   "a\nmine\nc\n",
+			generated: "a\ntheirs\nc\n",
+			want:      "a\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> gostart\nc\n",
+		},
+		{
+			name:      "added and removed lines",
+			current:   "a\nextra\nb\n",
+			generated: "a\nb\nnew\n",
+			want:      "a\n<<<<<<< current\nextra\n=======\n>>>>>>> gostart\nb\n<<<<<<< current\n=======\nnew\n>>>>>>> gostart\n",
+		},
+		{
+			name:      "no newline at end of file",
+			current:   "a\nb",
+			generated: "a\nc",
+			want:      "a\n<<<<<<< current\nb\n=======\nc\n>>>>>>> gostart\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(mergeConflict([]byte(tt.current), []byte(tt.generated))); got != tt.want {
+				t.Errorf("mergeConflict() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,23 +2,12 @@ package cmd
 
 import (
 	"bytes"
-	_ "embed"
 	"fmt"
-	"log"
-	"os"
 	"strings"
-	"text/template"
 
-	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
 )
 
-//go:embed templates/dockerfile.tmpl
-var dockerfileTemplate string
-
-//go:embed templates/docker_compose.tmpl
-var dockerComposeTemplate string
-
 var DockerCmd = &cobra.Command{
 	Use:   "docker [name]",
 	Short: "Generate Dockerfile and docker-compose.yml",
@@ -33,42 +22,53 @@ var DockerCmd = &cobra.Command{
 }
 
 func generateDockerfile() {
-	tmpl, err := template.New("Dockerfile").Parse(dockerfileTemplate)
+	tmpl, err := parseTemplate("dockerfile.tmpl")
 	if err != nil {
-		log.Fatalf("❌ Failed to parse Dockerfile template: %v", err)
+		fatalf("❌ Failed to parse Dockerfile template: %v", err)
 	}
 
+	moduleName, _ := getModuleName()
+
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, nil); err != nil {
-		log.Fatalf("❌ Failed to execute Dockerfile template: %v", err)
+	if err := tmpl.Execute(&buf, newTemplateData(moduleName)); err != nil {
+		fatalf("❌ Failed to execute Dockerfile template: %v", err)
 	}
 
 	dst := "Dockerfile"
-	if err := os.WriteFile(dst, buf.Bytes(), 0644); err != nil {
-		log.Fatalf("❌ Error generating Dockerfile: %v", err)
+	written, err := writeGenerated(dst, buf.Bytes())
+	if err != nil {
+		fatalf("❌ Error generating Dockerfile: %v", err)
+	}
+	if written {
+		recordGenerated("docker", "Dockerfile", dst, "dockerfile.tmpl", buf.Bytes())
+		fmt.Println("✅ Dockerfile generated successfully.")
 	}
-	fmt.Println("✅ Dockerfile generated successfully.")
 }
 
 func generateDockerCompose(serviceName string) {
-	tmpl, err := template.New("docker-compose").Parse(dockerComposeTemplate)
+	tmpl, err := parseTemplate("docker_compose.tmpl")
 	if err != nil {
-		log.Fatalf("❌ Failed to parse docker-compose template: %v", err)
+		fatalf("❌ Failed to parse docker-compose template: %v", err)
 	}
 
-	data := types.TemplateData{
-		ServiceName:      serviceName,
-		ServiceNameLower: strings.ToLower(serviceName),
-	}
+	moduleName, _ := getModuleName()
+
+	data := newTemplateData(moduleName)
+	data.ServiceName = serviceName
+	data.ServiceNameLower = strings.ToLower(serviceName)
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		log.Fatalf("❌ Failed to execute docker-compose template: %v", err)
+		fatalf("❌ Failed to execute docker-compose template: %v", err)
 	}
 
 	dst := "docker-compose.yaml"
-	if err := os.WriteFile(dst, buf.Bytes(), 0644); err != nil {
-		log.Fatalf("❌ Error generating docker-compose.yaml: %v", err)
+	written, err := writeGenerated(dst, buf.Bytes())
+	if err != nil {
+		fatalf("❌ Error generating docker-compose.yaml: %v", err)
+	}
+	if written {
+		recordGenerated("docker", "docker-compose", dst, "docker_compose.tmpl", buf.Bytes())
+		fmt.Println("✅ docker-compose.yaml generated successfully.")
 	}
-	fmt.Println("✅ docker-compose.yaml generated successfully.")
 }
@@ -1,25 +1,32 @@
 package cmd
 
 import (
+	"fmt"
+	"go/ast"
+	"go/token"
 	"log"
-	"os"
 	"path/filepath"
-	"strings"
 
+	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
+// routePath overrides the path create feature mounts the handler at.
+var routePath string
+
 var FeatureCmd = &cobra.Command{
 	Use:   "feature [name]",
 	Short: "Create usecase, repository, and handler, and inject to bootstrap.go",
+	Long:  "Create usecase, repository, and handler, inject them to bootstrap.go and mount the handler routes in router.go. With --fields it also creates the model and request DTOs and generates full CRUD code.",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := strings.ToLower(args[0])
-		pascal := cases.Title(language.English).String(name)
-		log.Println("🚀 Generating feature:", name)
+		n := mustParseComponentName(args[0])
+		log.Println("🚀 Generating feature:", n.Raw)
 
+		crud := fieldSpec != ""
+		if crud {
+			ModelCmd.Run(ModelCmd, args)
+		}
 		if UsecaseCmd.Run != nil {
 			UsecaseCmd.Run(UsecaseCmd, args)
 		}
@@ -30,147 +37,266 @@ var FeatureCmd = &cobra.Command{
 			HandlerCmd.Run(HandlerCmd, args)
 		}
 
-		// Bootstrap path
-		bootstrapPath := filepath.Join("internal", "app", "bootstrap", "bootstrap.go")
-		if _, err := os.Stat(bootstrapPath); os.IsNotExist(err) {
-			err := os.MkdirAll(filepath.Dir(bootstrapPath), os.ModePerm)
-			if err != nil {
-				log.Fatalf("❌ Failed to create bootstrap directory: %v", err)
+		bootstrapPath := bootstrapFilePath()
+		if !fileExists(bootstrapPath) {
+			data := newTemplateData(resolveModuleName())
+			if _, err := renderTemplate(bootstrapPath, "bootstrap.tmpl", data); err != nil {
+				fatalf("❌ Failed to create bootstrap.go: %v", err)
 			}
+			log.Println("📦 Created new bootstrap.go")
+		}
 
-			initialContent := `package bootstrap
+		if err := injectToBootstrap(n); err != nil {
+			log.Printf("❌ Failed to inject to bootstrap: %v", err)
+		} else {
+			log.Println("✅ Injected to bootstrap.go")
+		}
 
-import (
-	"gorm.io/gorm"
-)
+		mountPath := routePath
+		if mountPath == "" {
+			mountPath = routeMountPath(n)
+		}
+		if err := injectRoute(n.Pascal, routeGroup(), mountPath); err != nil {
+			log.Printf("❌ Failed to register routes: %v", err)
+		} else {
+			log.Println("✅ Routes registered in router.go")
+		}
+	},
+}
 
-type Dependencies struct {
-	DB *gorm.DB
+// bootstrapFilePath is the file wiring every dependency, e.g. internal/app/bootstrap/bootstrap.go.
+func bootstrapFilePath() string {
+	return filepath.Join(projectConfig().Layers.Bootstrap.Dir, "bootstrap.go")
 }
 
-func InitDependencies() *Dependencies {
-	db, err := database.ConnectDB()
+// injectToBootstrap wires the repository, usecase and handler of a feature
+// into bootstrap.go: imports, constructor calls, the Dependencies field and
+// the matching entry in the returned literal.
+func injectToBootstrap(n types.Name) error {
+	module, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
 
+	b, err := parseBootstrap()
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return err
 	}
 
-	// Repositories
+	layers := projectConfig().templateLayers(module)
+	repoPkg := b.ensureImport(layers.Repositories.Import, layers.Repositories.Package)
+	usecasePkg := b.ensureImport(layers.Usecases.Import, layers.Usecases.Package)
+	handlers := nestedLayer(layers.Handlers, n.Parents)
+	handlerPkg := b.ensureImport(handlers.Import, handlers.Package)
+	db, logger := b.db(), b.logger()
 
-	// Usecases
+	pascal := n.Pascal
+	repoVar := n.Camel + "Repo"
+	usecaseVar := n.Camel + "Usecase"
+	handlerVar := n.Camel + "Handler"
 
-	// Handlers
+	b.injectWiring([]bootstrapWiring{
+		{repoVar, repoPkg, "Repositories", repoVar + " := " + repoPkg + ".New" + pascal + "Repository(" + db + ", " + logger + ")", "Repository"},
+		{usecaseVar, usecasePkg, "Usecases", usecaseVar + " := " + usecasePkg + ".New" + pascal + "Usecase(" + repoVar + ", " + logger + ")", "Usecase"},
+		{handlerVar, handlerPkg, "Handlers", handlerVar + " := " + handlerPkg + ".New" + pascal + "Handler(" + usecaseVar + ", " + logger + ")", "Handler"},
+	})
+	b.injectDependency(pascal+"Handler", "*"+handlerPkg+"."+pascal+"Handler", handlerVar)
 
-	return &Dependencies{
-		DB: db,
+	out, err := b.bytes()
+	if err != nil {
+		return err
 	}
+	return writeTracked(b.path, out)
 }
-`
-			if err := os.WriteFile(bootstrapPath, []byte(initialContent), 0644); err != nil {
-				log.Fatalf("❌ Failed to create bootstrap.go: %v", err)
-			}
-			log.Println("📦 Created new bootstrap.go")
-		}
 
-		if err := injectToBootstrap(name, pascal); err != nil {
-			log.Printf("❌ Failed to inject to bootstrap: %v", err)
-		} else {
-			log.Println("✅ Injected to bootstrap.go")
-		}
-	},
+// bootstrapSource is bootstrap.go with the declarations the wiring edits.
+type bootstrapSource struct {
+	*goSource
+	deps *ast.StructType   // type Dependencies struct
+	fn   *ast.FuncDecl     // func InitDependencies
+	ret  *ast.ReturnStmt   // return &Dependencies{...}
+	lit  *ast.CompositeLit // Dependencies{...}
 }
 
-func injectToBootstrap(name, pascal string) error {
-	module, _ := getModuleName()
-	path := "internal/app/bootstrap/bootstrap.go"
-	contentBytes, err := os.ReadFile(path)
+func parseBootstrap() (*bootstrapSource, error) {
+	bootstrapPath := bootstrapFilePath()
+	src, err := readFile(bootstrapPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	content := string(contentBytes)
-
-	imports := map[string]string{
-		`"log"`: "log",
-		`"` + module + `/internal/infrastructure/database"`:     "database",
-		`"` + module + `/internal/infrastructure/repositories"`: "repositories",
-		`"` + module + `/internal/interface/handlers"`:          "handlers",
-		`"` + module + `/internal/app/usecases"`:                "usecases",
+	s, err := parseGoSource(bootstrapPath, src)
+	if err != nil {
+		return nil, err
 	}
 
-	// Inject missing imports
-	for full, pkg := range imports {
-		if !strings.Contains(content, pkg) {
-			content = injectImport(content, full)
-		}
+	b := &bootstrapSource{goSource: s}
+	if b.deps = s.findStruct("Dependencies"); b.deps == nil {
+		return nil, fmt.Errorf("%s: expected a `type Dependencies struct { ... }` declaration", bootstrapPath)
+	}
+	if b.fn = s.findFunc("InitDependencies"); b.fn == nil {
+		return nil, fmt.Errorf("%s: expected an `InitDependencies` function returning `&Dependencies{ ... }`", bootstrapPath)
+	}
+	if b.ret, b.lit = returnedComposite(b.fn, "Dependencies"); b.lit == nil {
+		return nil, fmt.Errorf("%s: InitDependencies must end with `return &Dependencies{ ... }`", bootstrapPath)
 	}
+	return b, nil
+}
 
-	// Inject Repository
-	repoLine := name + "Repo := repositories.New" + pascal + "Repository(db)"
-	if !strings.Contains(content, repoLine) {
-		content = injectAfter(content, "// Repositories", "\n\t"+repoLine)
+// db returns the expression of the *gorm.DB the repositories get.
+func (b *bootstrapSource) db() string {
+	if db := compositeValue(b.lit, "DB"); db != "" {
+		return db
 	}
+	return "db"
+}
 
-	// Inject Usecase
-	usecaseLine := name + "Usecase := usecases.New" + pascal + "Usecase(" + name + "Repo)"
-	if !strings.Contains(content, usecaseLine) {
-		content = injectAfter(content, "// Usecases", "\n\t"+usecaseLine)
+// logger returns the expression of the *slog.Logger every layer gets.
+// Bootstraps generated before the logging package have no logger to pass.
+func (b *bootstrapSource) logger() string {
+	if logger := compositeValue(b.lit, "Logger"); logger != "" {
+		return logger
 	}
+	if declaredVars(b.fn)["logger"] || paramNames(b.fn)["logger"] {
+		return "logger"
+	}
+	return b.ensureImport("log/slog", "slog") + ".Default()"
+}
 
-	// Inject Handler
-	handlerLine := name + "Handler := handler.New" + pascal + "Handler(" + name + "Usecase)"
-	if !strings.Contains(content, handlerLine) {
-		content = injectAfter(content, "// Handlers", "\n\t"+handlerLine)
+// config returns the expression of the *config.Config, e.g. cfg.
+func (b *bootstrapSource) config() string {
+	if cfg := compositeValue(b.lit, "Config"); cfg != "" {
+		return cfg
 	}
+	return "cfg"
+}
+
+// bootstrapWiring is a statement of InitDependencies declaring variable,
+// placed after the last pkg.New... call, else after the marker comment.
+type bootstrapWiring struct {
+	variable, pkg, marker, line string
+	suffix                      string // of the constructors it follows, e.g. Handler
+}
 
-	// Inject return
-	returnLine := pascal + "Handler: " + name + "Handler,"
-	if !strings.Contains(content, returnLine) {
-		content = injectAfter(content, "return &Dependencies{", "\n\t\t"+returnLine)
+// injectWiring adds the statements of wiring that InitDependencies lacks. A
+// statement with neither a constructor call nor a marker to follow goes
+// right after the previous one, or before the return for the first.
+func (b *bootstrapSource) injectWiring(wiring []bootstrapWiring) {
+	declared := declaredVars(b.fn)
+	prev := token.NoPos
+	for _, w := range wiring {
+		if declared[w.variable] {
+			continue
+		}
+		if last := lastConstructorCall(b.fn, w.pkg, w.suffix); last != nil {
+			prev = last.End()
+		} else if c := b.findComment(b.fn.Body, w.marker); c != nil {
+			prev = c.End()
+		} else if !prev.IsValid() {
+			b.insert(b.ret.Pos(), w.line+"\n\t")
+			continue
+		}
+		b.insert(prev, "\n\t"+w.line)
 	}
+}
 
-	// Inject Dependencies struct field
-	structLine := pascal + "Handler *handler." + pascal + "Handler"
-	if !strings.Contains(content, structLine) {
-		content = injectAfter(content, "type Dependencies struct {", "\n\t"+structLine)
+// injectDependency adds field to the Dependencies struct and sets it to value
+// in the literal InitDependencies returns.
+func (b *bootstrapSource) injectDependency(field, typ, value string) {
+	if !fieldNames(b.deps)[field] {
+		var last ast.Node
+		if n := len(b.deps.Fields.List); n > 0 {
+			last = b.deps.Fields.List[n-1]
+		}
+		b.insertLine(b.deps.Fields.Closing, last, field+" "+typ, false)
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	if !compositeKeys(b.lit)[field] {
+		var last ast.Node
+		if n := len(b.lit.Elts); n > 0 {
+			last = b.lit.Elts[n-1]
+		}
+		b.insertLine(b.lit.Rbrace, last, field+": "+value+",", true)
+	}
 }
 
-func injectImport(content, importLine string) string {
-	lines := strings.Split(content, "\n")
-	for i, line := range lines {
-		if strings.HasPrefix(line, "import (") {
-			// Cari akhir dari block import
-			for j := i + 1; j < len(lines); j++ {
-				if strings.HasPrefix(lines[j], ")") {
-					lines = append(lines[:j], append([]string{"\t" + importLine}, lines[j:]...)...)
-					return strings.Join(lines, "\n")
+// removeFromBootstrap undoes injectToBootstrap for the given layers
+// ("Repository", "Usecase", "Handler"): it drops their constructor calls, the
+// Dependencies field and literal entry of the handler, and the imports nothing
+// uses any more.
+func removeFromBootstrap(n types.Name, kinds ...string) error {
+	module, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
+
+	bootstrapPath := bootstrapFilePath()
+	if !fileExists(bootstrapPath) {
+		return nil
+	}
+	src, err := readFile(bootstrapPath)
+	if err != nil {
+		return err
+	}
+	s, err := parseGoSource(bootstrapPath, src)
+	if err != nil {
+		return err
+	}
+
+	constructors := make(map[string]bool)
+	for _, kind := range kinds {
+		constructors["New"+n.Pascal+kind] = true
+	}
+	field := ""
+	if constructors["New"+n.Pascal+"Handler"] {
+		field = n.Pascal + "Handler"
+	}
+
+	if fn := s.findFunc("InitDependencies"); fn != nil {
+		for _, stmt := range fn.Body.List {
+			assign, ok := stmt.(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				continue
+			}
+			call, ok := assign.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && constructors[sel.Sel.Name] {
+				s.remove(stmt)
+			}
+		}
+		if _, lit := returnedComposite(fn, "Dependencies"); lit != nil {
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if id, ok := kv.Key.(*ast.Ident); ok && field != "" && id.Name == field {
+						s.remove(elt)
+					}
 				}
 			}
 		}
 	}
-	return content
-}
 
-func injectAfter(content, marker, toInject string) string {
-	lines := strings.Split(content, "\n")
-	for i, line := range lines {
-		if strings.Contains(line, marker) {
-			indent := detectIndentation(lines, i+1)
-			lines = append(lines[:i+1], append([]string{indent + strings.TrimLeft(toInject, "\n\t")}, lines[i+1:]...)...)
-			break
+	if deps := s.findStruct("Dependencies"); deps != nil {
+		for _, f := range deps.Fields.List {
+			if len(f.Names) == 1 && field != "" && f.Names[0].Name == field {
+				s.remove(f)
+			}
 		}
 	}
-	return strings.Join(lines, "\n")
-}
 
-func detectIndentation(lines []string, start int) string {
-	for i := start; i < len(lines); i++ {
-		line := lines[i]
-		if strings.TrimSpace(line) != "" {
-			return line[:len(line)-len(strings.TrimLeft(line, "\t "))]
-		}
+	if len(s.edits) == 0 {
+		return nil
+	}
+
+	layers := projectConfig().templateLayers(module)
+	handlers := nestedLayer(layers.Handlers, n.Parents)
+	for _, l := range []types.Layer{layers.Repositories, layers.Usecases, handlers} {
+		s.removeUnusedImport(l.Import, l.Package)
+	}
+
+	out, err := s.bytes()
+	if err != nil {
+		return err
 	}
-	return "\t"
+	return writeTracked(bootstrapPath, out)
 }
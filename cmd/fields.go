@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+// fieldSpec is bound to the --fields flag of the create commands, e.g.
+// "customer_id:uint:fk,total:decimal,status:enum(pending,paid),note:string?".
+var fieldSpec string
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// fieldTypes maps spec types to their Go type and gorm column tag.
+var fieldTypes = map[string]struct{ goType, gorm string }{
+	"string":   {"string", "type:varchar(255)"},
+	"text":     {"string", "type:text"},
+	"int":      {"int", ""},
+	"int64":    {"int64", ""},
+	"uint":     {"uint", ""},
+	"float":    {"float64", ""},
+	"decimal":  {"float64", "type:decimal(12,2)"},
+	"bool":     {"bool", ""},
+	"time":     {"time.Time", ""},
+	"datetime": {"time.Time", ""},
+	"date":     {"time.Time", "type:date"},
+	"uuid":     {"string", "type:char(36)"},
+}
+
+// parseFields parses a --fields specification. Each field is
+// name:type[?][:modifier...], where a trailing ? makes the field nullable and
+// the modifiers are fk, index and unique.
+func parseFields(spec string) ([]types.Field, error) {
+	var fields []types.Field
+	seen := make(map[string]bool)
+
+	for _, part := range splitTopLevel(spec, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		segments := splitTopLevel(part, ':')
+		if len(segments) < 2 {
+			return nil, fmt.Errorf("field %q: expected name:type", part)
+		}
+
+		name := strings.TrimSpace(segments[0])
+		if !fieldNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("field %q: name must be snake_case, e.g. customer_id", name)
+		}
+		if name == "id" || name == "created_at" || name == "updated_at" {
+			return nil, fmt.Errorf("field %q is generated automatically", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("field %q is declared twice", name)
+		}
+		seen[name] = true
+
+		field, err := parseFieldType(name, strings.TrimSpace(segments[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []string
+		if field.GormTag != "" {
+			tags = append(tags, field.GormTag)
+		}
+		for _, modifier := range segments[2:] {
+			switch strings.TrimSpace(modifier) {
+			case "fk":
+				field.ForeignKey = true
+				tags = append(tags, "index")
+			case "index":
+				tags = append(tags, "index")
+			case "unique":
+				tags = append(tags, "uniqueIndex")
+			default:
+				return nil, fmt.Errorf("field %q: unknown modifier %q (use fk, index or unique)", name, modifier)
+			}
+		}
+		if !field.Nullable {
+			tags = append(tags, "not null")
+		}
+		field.GormTag = strings.Join(tags, ";")
+
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields in %q", spec)
+	}
+	return fields, nil
+}
+
+func parseFieldType(name, spec string) (types.Field, error) {
+	field := types.Field{
+		Name:     name,
+		GoName:   snakeToPascal(name),
+		JSONName: name,
+	}
+
+	if strings.HasSuffix(spec, "?") {
+		field.Nullable = true
+		spec = strings.TrimSuffix(spec, "?")
+	}
+
+	if strings.HasPrefix(spec, "enum(") && strings.HasSuffix(spec, ")") {
+		longest := 0
+		for _, v := range strings.Split(spec[len("enum("):len(spec)-1], ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			field.EnumValues = append(field.EnumValues, v)
+			longest = max(longest, len(v))
+		}
+		if len(field.EnumValues) == 0 {
+			return field, fmt.Errorf("field %q: enum needs at least one value", name)
+		}
+		field.Type = "enum"
+		field.BaseType = "string"
+		field.GormTag = fmt.Sprintf("type:varchar(%d)", max(longest, 20))
+	} else {
+		t, ok := fieldTypes[spec]
+		if !ok {
+			return field, fmt.Errorf("field %q: unknown type %q", name, spec)
+		}
+		field.Type = spec
+		field.BaseType = t.goType
+		field.GormTag = t.gorm
+	}
+
+	field.GoType = field.BaseType
+	if field.Nullable {
+		field.GoType = "*" + field.BaseType
+	}
+	return field, nil
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside parentheses.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// templateFields parses the --fields flag, returning nil when it is not set.
+func templateFields() []types.Field {
+	if fieldSpec == "" {
+		return nil
+	}
+	fields, err := parseFields(fieldSpec)
+	if err != nil {
+		fatalf("❌ Invalid --fields: %v", err)
+	}
+	return fields
+}
+
+// addFieldsFlag registers --fields on a create command.
+func addFieldsFlag(c *cobra.Command) {
+	c.Flags().StringVar(&fieldSpec, "fields", "", `Generate CRUD code for these fields, e.g. "customer_id:uint:fk,total:decimal,status:enum(pending,paid),note:string?"`)
+}
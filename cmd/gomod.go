@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"golang.org/x/mod/modfile"
+)
+
+// requireModules adds the modules the generated code imports to go.mod, at
+// the versions from moduleVersions. Modules already required keep their
+// version. A project without go.mod is left alone.
+func requireModules(paths ...string) error {
+	content, err := readFile("go.mod")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	required := make(map[string]bool)
+	for _, r := range f.Require {
+		required[r.Mod.Path] = true
+	}
+
+	changed := false
+	for _, path := range paths {
+		if required[path] {
+			continue
+		}
+		version, ok := moduleVersions[path]
+		if !ok {
+			return fmt.Errorf("no known version of %s", path)
+		}
+		f.AddNewRequire(path, version, false)
+		required[path] = true
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	f.SortBlocks()
+	f.Cleanup()
+	out, err := f.Format()
+	if err != nil {
+		return fmt.Errorf("failed to format go.mod: %w", err)
+	}
+	return writeFile("go.mod", out)
+}
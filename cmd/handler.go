@@ -1,74 +1,115 @@
 package cmd
 
 import (
-	"bytes"
-	_ "embed"
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
 
 	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-//go:embed templates/handler.tmpl
-var handlerTmpl string
-
 var HandlerCmd = &cobra.Command{
 	Use:   "handler [name]",
 	Short: "Create a new handler",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		// Convert name to lowercase and Title case
-		name := strings.ToLower(args[0])
-		caser := cases.Title(language.English)
+		n := mustParseComponentName(args[0])
+
+		// Get module name from go.mod
+		moduleName, err := getModuleName()
+		if err != nil {
+			fatalf("❌ Failed to get module name from go.mod: %v", err)
+		}
 
-		// Handle nested path like "task/comment"
-		parts := strings.Split(name, "/")
-		last := parts[len(parts)-1]
-		serviceName := caser.String(last)
+		// Prepare data, nested names like "shop/order_item" get a sub-package
+		templateData := newComponentData(moduleName, n)
+		templateData.Layers.Handlers = nestedLayer(templateData.Layers.Handlers, n.Parents)
+		templateData.Fields = templateFields()
 
 		// Destination directory
-		destDir := filepath.Join("internal/interface/handlers")
-		if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
-			log.Fatalf("❌ Failed to create handler directory: %v", err)
+		cfg := projectConfig()
+		destDir := filepath.FromSlash(templateData.Layers.Handlers.Dir)
+		if err := makeDir(destDir); err != nil {
+			fatalf("❌ Failed to create handler directory: %v", err)
 		}
 
-		// Parse embedded template
-		tmpl, err := template.New("handler").Parse(handlerTmpl)
+		// Parse template, field-based generation gets the CRUD handler
+		handlerTmpl := "handler.tmpl"
+		if len(templateData.Fields) > 0 {
+			handlerTmpl = "handler_crud.tmpl"
+		}
+		content, err := executeTemplate(handlerTmpl, templateData)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse embedded template: %v", err)
+			fatalf("❌ %v", err)
 		}
 
-		// Get module name from go.mod
-		moduleName, err := getModuleName()
+		// Output file path
+		outputPath := filepath.Join(destDir, fileName(cfg.Files.Handler, n.Snake))
+		written, err := writeGenerated(outputPath, content)
 		if err != nil {
-			log.Fatalf("❌ Failed to get module name from go.mod: %v", err)
+			fatalf("❌ Failed to write handler file: %v", err)
 		}
 
-		// Prepare data
-		templateData := types.TemplateData{
-			ServiceName:      serviceName,
-			ServiceNameLower: last,
-			ModuleName:       moduleName,
+		if written {
+			recordGenerated("handler", componentName(n), outputPath, handlerTmpl, content)
+			fmt.Println("✅ Handler created at:", outputPath)
 		}
 
-		var buf bytes.Buffer
-		if err := tmpl.Execute(&buf, templateData); err != nil {
-			log.Fatalf("❌ Failed to execute template: %v", err)
+		if len(templateData.Fields) > 0 {
+			ensurePagination(templateData)
+			ensureAppErrors(templateData)
+			generateRequestDTO(templateData)
 		}
 
-		// Output file path
-		outputPath := filepath.Join(destDir, fmt.Sprintf("%s_handler.go", last))
-		if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-			log.Fatalf("❌ Failed to write handler file: %v", err)
+		// The handler is tested through its routes with a mocked usecase
+		testTmpl := "handler_test.tmpl"
+		if len(templateData.Fields) > 0 {
+			testTmpl = "handler_crud_test.tmpl"
+		}
+		if err := generateTest("handler", n, filepath.Join(destDir, testFileName(cfg.Files.Handler, n.Snake)), testTmpl, templateData); err != nil {
+			fatalf("❌ %v", err)
 		}
-
-		fmt.Println("✅ Handler created at:", outputPath)
 	},
 }
+
+// generateRequestDTO writes the create and update request bodies of a CRUD
+// handler into the request package.
+func generateRequestDTO(data types.TemplateData) {
+	cfg := projectConfig()
+	if err := makeDir(cfg.Layers.Request.Dir); err != nil {
+		fatalf("❌ Failed to create request directory: %v", err)
+	}
+
+	ensureValidation(data)
+
+	content, err := executeTemplate("request_dto.tmpl", data)
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+
+	outputPath := filepath.Join(cfg.Layers.Request.Dir, fileName(cfg.Files.Request, data.Name.Snake))
+	written, err := writeGenerated(outputPath, content)
+	if err != nil {
+		fatalf("❌ Failed to write request file: %v", err)
+	}
+	if written {
+		recordGenerated("handler", componentName(data.Name), outputPath, "request_dto.tmpl", content)
+		fmt.Println("✅ Request DTOs created at:", outputPath)
+	}
+}
+
+// ensureValidation generates the validation of the request package in
+// projects created before it.
+func ensureValidation(data types.TemplateData) {
+	path := filepath.Join(projectConfig().Layers.Request.Dir, "validate.go")
+	if fileExists(path) {
+		return
+	}
+	written, err := generateFile("request", "validation", path, "request_validate.tmpl", data)
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	if written {
+		fmt.Println("✅ Created:", path)
+	}
+}
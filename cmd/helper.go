@@ -8,6 +8,10 @@ import (
 )
 
 func getModuleName() (string, error) {
+	if module := projectConfig().Module; module != "" {
+		return module, nil
+	}
+
 	file, err := os.Open("go.mod")
 	if err != nil {
 		return "", err
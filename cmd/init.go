@@ -1,21 +1,17 @@
 package cmd
 
 import (
-	"bytes"
-	"embed"
 	"fmt"
 	"log"
-	"os"
+	"maps"
 	"path/filepath"
-	"text/template"
+	"slices"
+	"strings"
 
 	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
 )
 
-//go:embed templates/*
-var templateFS embed.FS
-
 var InitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize Go project structure",
@@ -23,39 +19,114 @@ var InitCmd = &cobra.Command{
 	Run:   runInit,
 }
 
+// initDatabase, initRouter and initErrors are bound to init --db, --router
+// and --errors.
+var (
+	initDatabase string
+	initRouter   string
+	initErrors   string
+)
+
+func init() {
+	InitCmd.Flags().StringVar(&initDatabase, "db", "", "Database driver: "+strings.Join(databaseNames(), ", ")+" (default mysql)")
+	InitCmd.Flags().StringVar(&initRouter, "router", "", "HTTP router: "+strings.Join(routerNames(), ", ")+" (default chi)")
+	InitCmd.Flags().StringVar(&initErrors, "errors", "", "Error response format: "+strings.Join(errorFormats, ", ")+" (default envelope)")
+}
+
 func runInit(cmd *cobra.Command, args []string) {
 	fmt.Println("🚀 Initializing Go project structure...")
 
-	createFolders()
+	if initDatabase != "" {
+		db, err := lookupDatabase(initDatabase)
+		if err != nil {
+			fatalf("❌ Invalid --db: %v", err)
+		}
+		if fileExists(projectConfigPath) && projectConfig().Database.Driver != db.Driver {
+			fmt.Printf("⚠️ %s uses %s, generating for %s as asked. Update its database section to keep them in sync.\n", projectConfigPath, projectConfig().Database.Driver, db.Driver)
+		}
+		projectConfig().Database.Driver = db.Driver
+	}
+	if initRouter != "" {
+		if _, err := lookupRouter(initRouter); err != nil {
+			fatalf("❌ Invalid --router: %v", err)
+		}
+		if fileExists(projectConfigPath) && projectConfig().Router != initRouter {
+			fmt.Printf("⚠️ %s uses %s, generating for %s as asked. Update its router key to keep them in sync.\n", projectConfigPath, projectConfig().Router, initRouter)
+		}
+		projectConfig().Router = initRouter
+	}
+	if initErrors != "" {
+		if err := lookupErrorFormat(initErrors); err != nil {
+			fatalf("❌ Invalid --errors: %v", err)
+		}
+		if fileExists(projectConfigPath) && projectConfig().Response.Errors != initErrors {
+			fmt.Printf("⚠️ %s uses %s errors, generating %s as asked. Update its response section to keep them in sync.\n", projectConfigPath, projectConfig().Response.Errors, initErrors)
+		}
+		projectConfig().Response.Errors = initErrors
+	}
+
 	moduleName := resolveModuleName()
+	writeInitialConfig()
+	createFolders()
 
-	data := types.TemplateData{
-		ServiceName:      "",
-		ServiceNameLower: "",
-		ModuleName:       moduleName,
-	}
+	data := newTemplateData(moduleName)
 
 	generateTemplateFiles(data)
+	requireProjectModules(data)
 	printNextSteps()
 }
 
+// requireProjectModules adds the modules the generated files import to go.mod.
+func requireProjectModules(data types.TemplateData) {
+	modules := []string{"gorm.io/gorm", data.Database.Import, "github.com/joho/godotenv"}
+	if r, _ := lookupRouter(data.Router); r.module != "" {
+		modules = append(modules, r.module)
+	}
+	err := requireModules(modules...)
+	if err != nil {
+		fatalf("❌ Failed to update go.mod: %v", err)
+	}
+}
+
+// writeInitialConfig stores the layout init uses in .gostart.yaml, so later
+// commands and teammates share it. An existing file is left untouched.
+func writeInitialConfig() {
+	if fileExists(projectConfigPath) {
+		fmt.Println("🧩 Using existing", projectConfigPath)
+		return
+	}
+
+	cfg := *projectConfig()
+	if module, err := getModuleName(); err == nil {
+		cfg.Module = module
+	}
+	written, err := writeProjectConfig(&cfg)
+	if err != nil {
+		fatalf("❌ Failed to write %s: %v", projectConfigPath, err)
+	}
+	if written {
+		fmt.Printf("✅ Generated: %s\n", projectConfigPath)
+	}
+}
+
 func createFolders() {
+	layers := projectConfig().Layers
 	dirs := []string{
-		"internal/app/usecases",
-		"internal/app/config",
-		"internal/infrastructure/middlewares",
-		"internal/infrastructure/databases/models",
-		"internal/infrastructure/databases/queries",
-		"internal/infrastructure/repositories",
-		"internal/infrastructure/services",
-		"internal/interface/handlers",
-		"internal/interface/request",
-		"internal/interface/response",
+		layers.Usecases.Dir,
+		layers.Config.Dir,
+		layers.Middlewares.Dir,
+		layers.Models.Dir,
+		filepath.Join(layers.Database.Dir, "migrations"),
+		layers.Repositories.Dir,
+		layers.Services.Dir,
+		layers.Handlers.Dir,
+		layers.Request.Dir,
+		layers.Response.Dir,
 	}
 
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			log.Fatalf("❌ Failed to create directory %s: %v", dir, err)
+		if err := makeDir(dir); err != nil {
+			fatalf("❌ Failed to create directory %s: %v", dir, err)
 		}
 		fmt.Printf("📁 Created directory: %s\n", dir)
 	}
@@ -71,54 +142,100 @@ func resolveModuleName() string {
 }
 
 func generateTemplateFiles(data types.TemplateData) {
+	layers := data.Layers
 	files := map[string]string{
-		"cmd/main.go":                             "templates/main.tmpl",
-		"internal/app/bootstrap/bootstrap.go":     "templates/bootstrap.tmpl",
-		".air.toml":                               "templates/air.tmpl",
-		"internal/infrastructure/databases/db.go": "templates/db.tmpl",
-		// ".gitignore":                         "templates/gitignore.tmpl",
-		// "README.md":                                   "templates/readme.tmpl",
-		".env.example":                                     "templates/env.tmpl",
-		"internal/app/config/config.go":                    "templates/config.tmpl",
-		"internal/interface/response/response.go":          "templates/response.tmpl",
-		"internal/interface/request/request.go":            "templates/request.tmpl",
-		"internal/interface/routes/router.go":              "templates/router.tmpl",
-		"internal/infrastructure/databases/models/user.go": "templates/models.tmpl",
-	}
-
-	for outPath, tmplPath := range files {
-		if err := renderTemplate(outPath, tmplPath, data); err != nil {
-			log.Fatalf("❌ Failed to generate %s: %v", outPath, err)
+		"cmd/main.go":    "main.tmpl",
+		"cmd/migrate.go": "migrate_cmd.tmpl",
+		"cmd/seed.go":    "seed_cmd.tmpl",
+		filepath.Join(layers.Bootstrap.Dir, "bootstrap.go"): "bootstrap.tmpl",
+		".air.toml": "air.tmpl",
+		filepath.Join(layers.Database.Dir, "db.go"):                       "db.tmpl",
+		filepath.Join(layers.Database.Dir, "migrate.go"):                  "migrate.tmpl",
+		filepath.Join(layers.Database.Dir, "migrate_draft.go"):            "migrate_draft.tmpl",
+		filepath.Join(layers.Database.Dir, "migrations", "migrations.go"): "migrations.tmpl",
+		filepath.Join(layers.Database.Dir, "seeders", "seeders.go"):       "seeders.tmpl",
+		filepath.Join(layers.Database.Dir, "seeders", "fake.go"):          "seeder_fake.tmpl",
+		// ".gitignore":                         "gitignore.tmpl",
+		// "README.md":                                   "readme.tmpl",
+		".env.example": "env.tmpl",
+		filepath.Join(layers.Config.Dir, "config.go"):         "config.tmpl",
+		filepath.Join(layers.Logging.Dir, "logging.go"):       "logging.tmpl",
+		filepath.Join(layers.Logging.Dir, "gorm.go"):          "logging_gorm.tmpl",
+		filepath.Join(layers.Response.Dir, "response.go"):     "response.tmpl",
+		filepath.Join(layers.Response.Dir, "paginated.go"):    "response_paginated.tmpl",
+		filepath.Join(layers.Response.Dir, "error.go"):        "response_error.tmpl",
+		filepath.Join(layers.Errors.Dir, "apperror.go"):       "apperror.tmpl",
+		filepath.Join(layers.Pagination.Dir, "pagination.go"): "pagination.tmpl",
+		filepath.Join(layers.Request.Dir, "request.go"):       "request.tmpl",
+		filepath.Join(layers.Request.Dir, "validate.go"):      "request_validate.tmpl",
+		filepath.Join(layers.Routes.Dir, "router.go"):         "router.tmpl",
+		filepath.Join(layers.Models.Dir, "user.go"):           "models.tmpl",
+		filepath.Join(layers.Models.Dir, "registry.go"):       "model_registry.tmpl",
+	}
+	data.Models = []string{"User"}
+
+	// Sorted, so that --dry-run prints the same diff on every run
+	paths := slices.Sorted(maps.Keys(files))
+
+	// Every conflict is reported before anything is written, so a refused
+	// init leaves the project as it was
+	contents := make(map[string][]byte, len(files))
+	var conflicts []string
+	for _, outPath := range paths {
+		content, err := executeTemplate(files[outPath], data)
+		if err != nil {
+			fatalf("❌ Failed to generate %s: %v", outPath, err)
+		}
+		contents[outPath] = content
+		if err := generatedConflict(outPath, content); err != nil {
+			conflicts = append(conflicts, err.Error())
 		}
-		fmt.Printf("✅ Generated: %s\n", outPath)
 	}
-}
+	if len(conflicts) > 0 && !Force && !SkipExisting && !Interactive {
+		fatalf("❌ Nothing was generated:\n   %s", strings.Join(conflicts, "\n   "))
+	}
 
-func renderTemplate(outputPath, templatePath string, data types.TemplateData) error {
-	tmplBytes, err := templateFS.ReadFile(templatePath)
-	if err != nil {
-		return fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	for _, outPath := range paths {
+		written, err := writeRendered(outPath, files[outPath], contents[outPath], data)
+		if err != nil {
+			fatalf("❌ Failed to generate %s: %v", outPath, err)
+		}
+		if written {
+			fmt.Printf("✅ Generated: %s\n", outPath)
+		}
 	}
 
-	tmpl, err := template.New(filepath.Base(templatePath)).Parse(string(tmplBytes))
-	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", templatePath, err)
+	if err := generateInitialMigration(data); err != nil {
+		fatalf("❌ Failed to generate the users migration: %v", err)
 	}
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", templatePath, err)
+// renderTemplate renders a template to outputPath and reports
+// whether the file was written (an existing file may be kept).
+func renderTemplate(outputPath, templateName string, data types.TemplateData) (bool, error) {
+	content, err := executeTemplate(templateName, data)
+	if err != nil {
+		return false, err
 	}
+	return writeRendered(outputPath, templateName, content, data)
+}
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create directory for %s: %w", outputPath, err)
+// writeRendered writes the content rendered from templateName to outputPath,
+// like renderTemplate.
+func writeRendered(outputPath, templateName string, content []byte, data types.TemplateData) (bool, error) {
+	if err := makeDir(filepath.Dir(outputPath)); err != nil {
+		return false, fmt.Errorf("failed to create directory for %s: %w", outputPath, err)
 	}
 
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	written, err := writeGenerated(outputPath, content)
+	if err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	}
+	if written {
+		recordGenerated("project", data.ModuleName, outputPath, templateName, content)
 	}
 
-	return nil
+	return written, nil
 }
 
 func printNextSteps() {
@@ -0,0 +1,447 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sourceEdit replaces the bytes between offset and end of the original source
+// with text. Insertions have end == offset.
+type sourceEdit struct {
+	offset int
+	end    int
+	text   string
+}
+
+// goSource locates insertion points through the parsed AST and splices the
+// new code into the original text, so comments and hand-written layout survive.
+// Removals work the same way in reverse.
+type goSource struct {
+	path  string
+	src   []byte
+	fset  *token.FileSet
+	file  *ast.File
+	edits []sourceEdit
+
+	imports []string
+}
+
+func parseGoSource(path string, src []byte) (*goSource, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &goSource{path: path, src: src, fset: fset, file: file}, nil
+}
+
+func (s *goSource) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+func (s *goSource) insert(pos token.Pos, text string) {
+	offset := s.offset(pos)
+	s.edits = append(s.edits, sourceEdit{offset: offset, end: offset, text: text})
+}
+
+// remove deletes node together with a trailing comma. When node is alone on
+// its line the whole line goes, otherwise only the node itself.
+func (s *goSource) remove(node ast.Node) {
+	start, end := s.offset(node.Pos()), s.offset(node.End())
+	for end < len(s.src) && (s.src[end] == ' ' || s.src[end] == '\t') {
+		end++
+	}
+	if end < len(s.src) && s.src[end] == ',' {
+		end++
+	}
+
+	lineStart := strings.LastIndexByte(string(s.src[:start]), '\n') + 1
+	lineEnd := len(s.src)
+	if i := strings.IndexByte(string(s.src[end:]), '\n'); i >= 0 {
+		lineEnd = end + i + 1
+	}
+	if strings.TrimSpace(string(s.src[lineStart:start])) == "" && strings.TrimSpace(string(s.src[end:lineEnd])) == "" {
+		start, end = lineStart, lineEnd
+	}
+	s.edits = append(s.edits, sourceEdit{offset: start, end: end})
+}
+
+// removed reports whether pos lies inside a pending removal.
+func (s *goSource) removed(pos token.Pos) bool {
+	offset := s.offset(pos)
+	for _, e := range s.edits {
+		if e.offset <= offset && offset < e.end {
+			return true
+		}
+	}
+	return false
+}
+
+// removeUnusedImport drops the import of path once no remaining code refers
+// to it.
+func (s *goSource) removeUnusedImport(path, pkg string) {
+	for _, imp := range s.file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+		name := pkg
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		used := false
+		ast.Inspect(s.file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok && !s.removed(sel.Pos()) {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == name {
+					used = true
+				}
+			}
+			return !used
+		})
+		if !used {
+			s.remove(imp)
+		}
+	}
+}
+
+// insertLine inserts text as a new line right before the closing token at pos,
+// adding a separating comma after last when the list needs one.
+func (s *goSource) insertLine(closing token.Pos, last ast.Node, text string, comma bool) {
+	prefix := ""
+	if last != nil && comma {
+		end := s.offset(last.End())
+		rest := strings.TrimLeft(string(s.src[end:s.offset(closing)]), " \t\r\n")
+		if !strings.HasPrefix(rest, ",") {
+			s.edits = append(s.edits, sourceEdit{offset: end, end: end, text: ","})
+		}
+	}
+	before := strings.TrimRight(string(s.src[:s.offset(closing)]), " \t")
+	if !strings.HasSuffix(before, "\n") {
+		prefix = "\n"
+	}
+	s.insert(closing, prefix+"\t"+text+"\n")
+}
+
+// ensureImport makes sure path is imported and returns the identifier the file
+// uses for it. pkg is the package name declared by the imported package.
+func (s *goSource) ensureImport(path, pkg string) string {
+	if name := s.importName(path, pkg); name != "" {
+		return name
+	}
+	for _, spec := range s.imports {
+		if strings.HasSuffix(spec, strconv.Quote(path)) {
+			return pkg
+		}
+	}
+
+	spec := strconv.Quote(path)
+	if pkg != importBase(path) {
+		spec = pkg + " " + spec
+	}
+	s.imports = append(s.imports, spec)
+	return pkg
+}
+
+// importName returns the identifier the file uses for the import of path,
+// or "" when the file does not import it.
+func (s *goSource) importName(path, pkg string) string {
+	for _, imp := range s.file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return pkg
+	}
+	return ""
+}
+
+// flushImports turns the collected import specs into edits.
+func (s *goSource) flushImports() {
+	if len(s.imports) == 0 {
+		return
+	}
+	module, _ := getModuleName()
+	var std, others []string
+	for _, spec := range s.imports {
+		path, _ := strconv.Unquote(spec[strings.LastIndexByte(spec, ' ')+1:])
+		if isStdImport(path, module) {
+			std = append(std, spec)
+		} else {
+			others = append(others, spec)
+		}
+	}
+	s.imports = nil
+
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if len(gen.Specs) == 0 {
+			s.insertLine(gen.Rparen, nil, importGroups(std, others), false)
+			return
+		}
+		if !gen.Lparen.IsValid() {
+			s.insert(gen.Specs[0].Pos(), "(\n\t")
+		}
+
+		// Standard library packages join the group of the existing ones,
+		// or open a group of their own at the top
+		var lastStd, lastOther ast.Spec
+		for _, spec := range gen.Specs {
+			if path, _ := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value); isStdImport(path, module) {
+				lastStd = spec
+			} else {
+				lastOther = spec
+			}
+		}
+		last := gen.Specs[len(gen.Specs)-1]
+		if len(std) > 0 {
+			if lastStd != nil {
+				s.insert(lastStd.End(), "\n\t"+strings.Join(std, "\n\t"))
+			} else {
+				s.insert(gen.Specs[0].Pos(), strings.Join(std, "\n\t")+"\n\n\t")
+			}
+		}
+		if len(others) > 0 {
+			sep := "\n\t"
+			if lastOther == nil {
+				sep = "\n\n\t"
+			}
+			s.insert(last.End(), sep+strings.Join(others, "\n\t"))
+		}
+
+		if !gen.Lparen.IsValid() {
+			s.insert(gen.End(), "\n)")
+		}
+		return
+	}
+
+	s.insert(s.file.Name.End(), "\n\nimport (\n\t"+importGroups(std, others)+"\n)\n")
+}
+
+// importGroups lays out import specs the way goimports does, the standard
+// library first and the other packages in a group of their own.
+func importGroups(std, others []string) string {
+	groups := []string{}
+	if len(std) > 0 {
+		groups = append(groups, strings.Join(std, "\n\t"))
+	}
+	if len(others) > 0 {
+		groups = append(groups, strings.Join(others, "\n\t"))
+	}
+	return strings.Join(groups, "\n\n\t")
+}
+
+// isStdImport reports whether path belongs to the standard library, whose
+// first path element has no dot unlike the path of other modules. The project
+// module may have a dotless path too, e.g. myapp.
+func isStdImport(path, module string) bool {
+	if path == module || strings.HasPrefix(path, module+"/") {
+		return false
+	}
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func importBase(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func (s *goSource) findStruct(name string) *ast.StructType {
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok && ts.Name.Name == name {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+func (s *goSource) findFunc(name string) *ast.FuncDecl {
+	for _, decl := range s.file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name && fn.Body != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// findComment returns the first comment inside node whose text is exactly text.
+func (s *goSource) findComment(node ast.Node, text string) *ast.Comment {
+	for _, group := range s.file.Comments {
+		if group.Pos() < node.Pos() || group.End() > node.End() {
+			continue
+		}
+		for _, c := range group.List {
+			if strings.TrimSpace(strings.TrimPrefix(c.Text, "//")) == text {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// bytes applies the pending edits and returns the gofmt-ed result.
+func (s *goSource) bytes() ([]byte, error) {
+	s.flushImports()
+	sort.SliceStable(s.edits, func(i, j int) bool {
+		return s.edits[i].offset < s.edits[j].offset
+	})
+
+	var buf strings.Builder
+	prev := 0
+	for _, e := range s.edits {
+		if e.offset > prev {
+			buf.Write(s.src[prev:e.offset])
+		}
+		buf.WriteString(e.text)
+		prev = max(prev, e.end)
+	}
+	buf.Write(s.src[prev:])
+
+	formatted, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: injected code does not compile: %w", s.path, err)
+	}
+	return formatted, nil
+}
+
+// fieldNames lists every field name declared in a struct.
+func fieldNames(st *ast.StructType) map[string]bool {
+	names := make(map[string]bool)
+	for _, field := range st.Fields.List {
+		for _, n := range field.Names {
+			names[n.Name] = true
+		}
+	}
+	return names
+}
+
+// compositeKeys lists every key used in a keyed composite literal.
+func compositeKeys(lit *ast.CompositeLit) map[string]bool {
+	keys := make(map[string]bool)
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if id, ok := kv.Key.(*ast.Ident); ok {
+				keys[id.Name] = true
+			}
+		}
+	}
+	return keys
+}
+
+// compositeValue returns the identifier assigned to key in lit, if any.
+func compositeValue(lit *ast.CompositeLit, key string) string {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if k, ok := kv.Key.(*ast.Ident); ok && k.Name == key {
+			if v, ok := kv.Value.(*ast.Ident); ok {
+				return v.Name
+			}
+		}
+	}
+	return ""
+}
+
+// returnedComposite finds `return &typeName{...}` in fn.
+func returnedComposite(fn *ast.FuncDecl, typeName string) (*ast.ReturnStmt, *ast.CompositeLit) {
+	var (
+		ret *ast.ReturnStmt
+		lit *ast.CompositeLit
+	)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		r, ok := n.(*ast.ReturnStmt)
+		if !ok || lit != nil {
+			return lit == nil
+		}
+		for _, res := range r.Results {
+			if u, ok := res.(*ast.UnaryExpr); ok && u.Op == token.AND {
+				res = u.X
+			}
+			cl, ok := res.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			if id, ok := cl.Type.(*ast.Ident); ok && id.Name == typeName {
+				ret, lit = r, cl
+			}
+		}
+		return false
+	})
+	return ret, lit
+}
+
+// declaredVars lists every variable declared with := in the top level of fn.
+func declaredVars(fn *ast.FuncDecl) map[string]bool {
+	vars := make(map[string]bool)
+	for _, stmt := range fn.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE {
+			continue
+		}
+		for _, lhs := range assign.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok {
+				vars[id.Name] = true
+			}
+		}
+	}
+	return vars
+}
+
+// paramNames lists the parameter names of fn.
+func paramNames(fn *ast.FuncDecl) map[string]bool {
+	names := make(map[string]bool)
+	for _, field := range fn.Type.Params.List {
+		for _, n := range field.Names {
+			names[n.Name] = true
+		}
+	}
+	return names
+}
+
+// lastConstructorCall returns the last top-level statement of fn that assigns
+// the result of a pkg.NewXxx(...) call, or of a NewXxxSuffix(...) call of any
+// package when suffix is set, such as shop.NewOrderItemHandler for Handler.
+func lastConstructorCall(fn *ast.FuncDecl, pkg, suffix string) ast.Stmt {
+	var last ast.Stmt
+	for _, stmt := range fn.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			continue
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok || !strings.HasPrefix(sel.Sel.Name, "New") {
+			continue
+		}
+		if x.Name == pkg || suffix != "" && strings.HasSuffix(sel.Sel.Name, suffix) {
+			last = stmt
+		}
+	}
+	return last
+}
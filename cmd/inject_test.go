@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"go/ast"
+	"testing"
+)
+
+// withModule makes getModuleName return module for the rest of the test.
+func withModule(t *testing.T, module string) {
+	t.Helper()
+	prev := loadedConfig
+	cfg := defaultProjectConfig()
+	cfg.Module = module
+	loadedConfig = cfg
+	t.Cleanup(func() { loadedConfig = prev })
+}
+
+func mustParseGoSource(t *testing.T, src string) *goSource {
+	t.Helper()
+	s, err := parseGoSource("f.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mustBytes(t *testing.T, s *goSource) string {
+	t.Helper()
+	out, err := s.bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestGoSourceEdits(t *testing.T) {
+	withModule(t, "example.com/app")
+	const src = `package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options configures the app.
+type Options struct {
+	Name string // kept
+}
+
+func Run() {
+	// Steps
+	fmt.Println("a")
+	b := strings.ToUpper("b")
+	_ = b
+}
+`
+	tests := []struct {
+		name string
+		edit func(s *goSource)
+		want string
+	}{
+		{
+			name: "insert field",
+			edit: func(s *goSource) {
+				st := s.findStruct("Options")
+				s.insertLine(st.Fields.Closing, st.Fields.List[0], "Debug bool", false)
+			},
+			want: `package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options configures the app.
+type Options struct {
+	Name  string // kept
+	Debug bool
+}
+
+func Run() {
+	// Steps
+	fmt.Println("a")
+	b := strings.ToUpper("b")
+	_ = b
+}
+`,
+		},
+		{
+			name: "insert after comment",
+			edit: func(s *goSource) {
+				fn := s.findFunc("Run")
+				s.insert(s.findComment(fn.Body, "Steps").End(), "\n\tfmt.Println(\"first\")")
+			},
+			want: `package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options configures the app.
+type Options struct {
+	Name string // kept
+}
+
+func Run() {
+	// Steps
+	fmt.Println("first")
+	fmt.Println("a")
+	b := strings.ToUpper("b")
+	_ = b
+}
+`,
+		},
+		{
+			name: "remove statements and the unused import",
+			edit: func(s *goSource) {
+				fn := s.findFunc("Run")
+				s.remove(fn.Body.List[1])
+				s.remove(fn.Body.List[2])
+				s.removeUnusedImport("strings", "strings")
+				s.removeUnusedImport("fmt", "fmt")
+			},
+			want: `package app
+
+import (
+	"fmt"
+)
+
+// Options configures the app.
+type Options struct {
+	Name string // kept
+}
+
+func Run() {
+	// Steps
+	fmt.Println("a")
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustParseGoSource(t, src)
+			tt.edit(s)
+			if got := mustBytes(t, s); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertLineComma(t *testing.T) {
+	s := mustParseGoSource(t, `package app
+
+var deps = map[string]int{
+	"a": 1,
+	"b": 2}
+`)
+	lit := s.file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Values[0].(*ast.CompositeLit)
+	s.insertLine(lit.Rbrace, lit.Elts[len(lit.Elts)-1], `"c": 3,`, true)
+
+	want := `package app
+
+var deps = map[string]int{
+	"a": 1,
+	"b": 2,
+	"c": 3,
+}
+`
+	if got := mustBytes(t, s); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlushImports(t *testing.T) {
+	tests := []struct {
+		name    string
+		module  string
+		src     string
+		imports [][2]string
+		want    string
+	}{
+		{
+			name:   "stdlib joins the stdlib group",
+			module: "example.com/app",
+			src: `package app
+
+import (
+	"net/http"
+
+	"example.com/app/internal/bootstrap"
+	"github.com/go-chi/chi/v5"
+)
+`,
+			imports: [][2]string{{"time", "time"}, {"example.com/app/internal/middlewares", "middlewares"}},
+			want: `package app
+
+import (
+	"net/http"
+	"time"
+
+	"example.com/app/internal/bootstrap"
+	"example.com/app/internal/middlewares"
+	"github.com/go-chi/chi/v5"
+)
+`,
+		},
+		{
+			name:   "stdlib opens a group at the top",
+			module: "example.com/app",
+			src: `package app
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+`,
+			imports: [][2]string{{"time", "time"}},
+			want: `package app
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+`,
+		},
+		{
+			name:   "others open a group after the stdlib",
+			module: "example.com/app",
+			src: `package app
+
+import (
+	"net/http"
+)
+`,
+			imports: [][2]string{{"example.com/app/internal/docs", "docs"}},
+			want: `package app
+
+import (
+	"net/http"
+
+	"example.com/app/internal/docs"
+)
+`,
+		},
+		{
+			name:   "single import gets parenthesized",
+			module: "example.com/app",
+			src: `package app
+
+import "net/http"
+`,
+			imports: [][2]string{{"github.com/labstack/echo/v4", "echo"}, {"time", "time"}},
+			want: `package app
+
+import (
+	"net/http"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+)
+`,
+		},
+		{
+			name:   "no import declaration",
+			module: "example.com/app",
+			src: `package app
+`,
+			imports: [][2]string{{"example.com/app/internal/logging", "applog"}, {"log/slog", "slog"}},
+			want: `package app
+
+import (
+	"log/slog"
+
+	applog "example.com/app/internal/logging"
+)
+`,
+		},
+		{
+			name:   "dotless module is not the stdlib",
+			module: "myapp",
+			src: `package app
+
+import (
+	"net/http"
+
+	"myapp/internal/bootstrap"
+)
+`,
+			imports: [][2]string{{"myapp/internal/middlewares", "middlewares"}, {"time", "time"}},
+			want: `package app
+
+import (
+	"net/http"
+	"time"
+
+	"myapp/internal/bootstrap"
+	"myapp/internal/middlewares"
+)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withModule(t, tt.module)
+			s := mustParseGoSource(t, tt.src)
+			for _, imp := range tt.imports {
+				if got := s.ensureImport(imp[0], imp[1]); got != imp[1] {
+					t.Errorf("ensureImport(%q) = %q, want %q", imp[0], got, imp[1])
+				}
+			}
+			if got := mustBytes(t, s); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureImportExisting(t *testing.T) {
+	s := mustParseGoSource(t, `package app
+
+import applog "example.com/app/internal/logging"
+`)
+	if got := s.ensureImport("example.com/app/internal/logging", "logging"); got != "applog" {
+		t.Errorf("ensureImport() = %q, want the existing name applog", got)
+	}
+	if len(s.imports) != 0 {
+		t.Errorf("ensureImport() planned %q for an imported package", s.imports)
+	}
+}
+
+func TestLastConstructorCall(t *testing.T) {
+	s := mustParseGoSource(t, `package bootstrap
+
+func InitDependencies() *Dependencies {
+	// Handlers
+	customerHandler := handler.NewCustomerHandler(customerUsecase, logger)
+	orderItemHandler := shop.NewOrderItemHandler(orderItemUsecase, logger)
+	tokenService, err := services.NewTokenService(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Dependencies{}
+}
+`)
+	fn := s.findFunc("InitDependencies")
+	tests := []struct {
+		pkg, suffix string
+		want        int // index in fn.Body.List, -1 for none
+	}{
+		{"handler", "", 0},
+		{"handler", "Handler", 1},
+		{"admin", "Handler", 1},
+		{"services", "", 2},
+		{"usecases", "Usecase", -1},
+	}
+	for _, tt := range tests {
+		got := lastConstructorCall(fn, tt.pkg, tt.suffix)
+		var want ast.Stmt
+		if tt.want >= 0 {
+			want = fn.Body.List[tt.want]
+		}
+		if got != want {
+			t.Errorf("lastConstructorCall(%q, %q) = statement %d, want %d", tt.pkg, tt.suffix, stmtIndex(fn, got), tt.want)
+		}
+	}
+}
+
+func stmtIndex(fn *ast.FuncDecl, stmt ast.Stmt) int {
+	for i, s := range fn.Body.List {
+		if s == stmt {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+// manifestPath records every file gostart generated in the project.
+const manifestPath = ".gostart/manifest.json"
+
+// Manifest is the content of .gostart/manifest.json.
+type Manifest struct {
+	Version    int                 `json:"version"`
+	Components []ManifestComponent `json:"components"`
+
+	dirty bool
+}
+
+// ManifestComponent is one generated component, e.g. the usecase shop/order_item.
+type ManifestComponent struct {
+	Kind  string         `json:"kind"` // project, model, usecase, repository, handler or docker
+	Name  string         `json:"name"` // e.g. shop/order_item
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a generated file and the content gostart last wrote to it.
+type ManifestFile struct {
+	Path            string `json:"path"`
+	Template        string `json:"template"`
+	TemplateVersion string `json:"template_version"`   // hash of the template source
+	Hash            string `json:"hash"`               // hash of the generated content
+	Injected        bool   `json:"injected,omitempty"` // gostart edited it in place since, e.g. bootstrap.go
+}
+
+var loadedManifest *Manifest
+
+// loadManifest returns the manifest, reading it on first use. A missing file
+// means nothing was recorded yet.
+func loadManifest() *Manifest {
+	if loadedManifest != nil {
+		return loadedManifest
+	}
+
+	m := &Manifest{Version: 1}
+	content, err := os.ReadFile(manifestPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		log.Fatalf("❌ Failed to read %s: %v", manifestPath, err)
+	default:
+		if err := json.Unmarshal(content, m); err != nil {
+			log.Fatalf("❌ Invalid %s: %v", manifestPath, err)
+		}
+	}
+
+	loadedManifest = m
+	return m
+}
+
+// SaveManifest writes the manifest back when a command changed it.
+func SaveManifest() {
+	m := loadedManifest
+	if m == nil || !m.dirty {
+		return
+	}
+
+	for _, c := range m.Components {
+		sort.Slice(c.Files, func(i, j int) bool { return c.Files[i].Path < c.Files[j].Path })
+	}
+	sort.Slice(m.Components, func(i, j int) bool {
+		if m.Components[i].Name != m.Components[j].Name {
+			return m.Components[i].Name < m.Components[j].Name
+		}
+		return m.Components[i].Kind < m.Components[j].Kind
+	})
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		log.Fatalf("❌ Failed to encode %s: %v", manifestPath, err)
+	}
+	if err := makeDir(filepath.Dir(manifestPath)); err != nil {
+		log.Fatalf("❌ Failed to create %s: %v", filepath.Dir(manifestPath), err)
+	}
+	if err := writeFile(manifestPath, append(content, '\n')); err != nil {
+		log.Fatalf("❌ Failed to write %s: %v", manifestPath, err)
+	}
+	m.dirty = false
+}
+
+// componentName is the name a component is recorded under, e.g. shop/order_item.
+func componentName(n types.Name) string {
+	return path.Join(append(append([]string{}, n.Parents...), n.Snake)...)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// templateVersion identifies the template source a file was generated from,
+// so a changed template can be told apart from a hand edit.
+func templateVersion(name string) string {
+	content, _, err := resolveTemplate(name)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:6])
+}
+
+func (m *Manifest) find(kind, name string) *ManifestComponent {
+	for i := range m.Components {
+		if m.Components[i].Kind == kind && m.Components[i].Name == name {
+			return &m.Components[i]
+		}
+	}
+	return nil
+}
+
+// file returns the record of the generated file at p, if any.
+func (m *Manifest) file(p string) *ManifestFile {
+	p = filepath.ToSlash(filepath.Clean(p))
+	for i := range m.Components {
+		for j := range m.Components[i].Files {
+			if m.Components[i].Files[j].Path == p {
+				return &m.Components[i].Files[j]
+			}
+		}
+	}
+	return nil
+}
+
+// owners returns the components p was generated for.
+func (m *Manifest) owners(p string) []*ManifestComponent {
+	p = filepath.ToSlash(filepath.Clean(p))
+	var owners []*ManifestComponent
+	for i := range m.Components {
+		for _, f := range m.Components[i].Files {
+			if f.Path == p {
+				owners = append(owners, &m.Components[i])
+				break
+			}
+		}
+	}
+	return owners
+}
+
+// record notes that p was generated from tmpl for the component kind/name.
+func (m *Manifest) record(kind, name, p, tmpl string, content []byte) {
+	p = filepath.ToSlash(filepath.Clean(p))
+	entry := ManifestFile{Path: p, Template: tmpl, TemplateVersion: templateVersion(tmpl), Hash: contentHash(content)}
+	m.dirty = true
+
+	// Other components generating the same file, such as models/registry.go
+	// written by init then by each model, must not see it as edited
+	for i := range m.Components {
+		for j := range m.Components[i].Files {
+			if f := &m.Components[i].Files[j]; f.Path == p {
+				f.Hash, f.Injected = entry.Hash, false
+			}
+		}
+	}
+
+	c := m.find(kind, name)
+	if c == nil {
+		m.Components = append(m.Components, ManifestComponent{Kind: kind, Name: name})
+		c = &m.Components[len(m.Components)-1]
+	}
+	for i := range c.Files {
+		if c.Files[i].Path == p {
+			c.Files[i] = entry
+			return
+		}
+	}
+	c.Files = append(c.Files, entry)
+}
+
+// touch updates the hash of a recorded file gostart modified itself, such as
+// bootstrap.go after an injection, so it is not mistaken for a hand edit.
+// The file no longer matches its template, so it is marked injected and never
+// regenerated without asking.
+func (m *Manifest) touch(p string, content []byte) {
+	p = filepath.ToSlash(filepath.Clean(p))
+	for i := range m.Components {
+		for j := range m.Components[i].Files {
+			if f := &m.Components[i].Files[j]; f.Path == p {
+				f.Hash, f.Injected = contentHash(content), true
+				m.dirty = true
+			}
+		}
+	}
+}
+
+// forget drops p from the component kind/name, and the component once it has
+// no files left.
+func (m *Manifest) forget(kind, name, p string) {
+	p = filepath.ToSlash(filepath.Clean(p))
+	for i := range m.Components {
+		c := &m.Components[i]
+		if c.Kind != kind || c.Name != name {
+			continue
+		}
+		for j := range c.Files {
+			if c.Files[j].Path == p {
+				c.Files = append(c.Files[:j], c.Files[j+1:]...)
+				m.dirty = true
+				break
+			}
+		}
+		if len(c.Files) == 0 {
+			m.Components = append(m.Components[:i], m.Components[i+1:]...)
+			m.dirty = true
+		}
+		return
+	}
+}
+
+// edited reports whether the recorded file at p no longer holds what gostart
+// wrote. Files gostart does not know about are never reported.
+func (m *Manifest) edited(p string) bool {
+	f := m.file(p)
+	if f == nil {
+		return false
+	}
+	current, err := readFile(p)
+	if err != nil {
+		return false
+	}
+	return contentHash(current) != f.Hash
+}
+
+// recordGenerated notes a file written by a generator in the manifest.
+func recordGenerated(kind, name, p, tmpl string, content []byte) {
+	loadManifest().record(kind, name, p, tmpl, content)
+}
+
+// writeTracked writes a file gostart edits in place, keeping its manifest
+// hash current.
+func writeTracked(p string, content []byte) error {
+	if err := writeFile(p, content); err != nil {
+		return err
+	}
+	loadManifest().touch(p, content)
+	return nil
+}
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the components gostart generated and the state of their files",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := loadManifest()
+		if len(m.Components) == 0 {
+			fmt.Printf("Nothing recorded in %s yet.\n", manifestPath)
+			return
+		}
+
+		for _, c := range m.Components {
+			fmt.Printf("📦 %-10s %s\n", c.Kind, c.Name)
+			for _, f := range c.Files {
+				fmt.Printf("   %s %s\n", fileStatus(m, f), f.Path)
+			}
+		}
+	},
+}
+
+// fileStatus describes a recorded file: missing, edited by hand, generated
+// from an older template, or unchanged.
+func fileStatus(m *Manifest, f ManifestFile) string {
+	switch {
+	case !fileExists(f.Path):
+		return "❌ missing  "
+	case m.edited(f.Path):
+		return "✏️  edited   "
+	case f.TemplateVersion != templateVersion(f.Template):
+		return "🆕 outdated "
+	}
+	return "✅ unchanged"
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+var MiddlewareCmd = &cobra.Command{
+	Use:   "middleware [name]",
+	Short: "Create a func(http.Handler) http.Handler middleware with a test",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		if len(n.Parents) > 0 {
+			fatalf("❌ Middlewares share one package, %q cannot be nested", n.Raw)
+		}
+
+		moduleName, _ := getModuleName()
+		data := newComponentData(moduleName, n)
+		dir := projectConfig().Layers.Middlewares.Dir
+
+		files := []struct{ path, tmpl string }{
+			{filepath.Join(dir, n.Snake+".go"), "middleware.tmpl"},
+			{filepath.Join(dir, n.Snake+"_test.go"), "middleware_test.tmpl"},
+		}
+		for _, f := range files {
+			if err := generateMiddlewareFile(componentName(n), f.path, f.tmpl, data); err != nil {
+				fatalf("❌ %v", err)
+			}
+		}
+		fmt.Printf("ℹ️  Register it in InitRouter, or wrap single routes with %s.%s\n", data.Layers.Middlewares.Package, n.Pascal)
+	},
+}
+
+var AddMiddlewareCmd = &cobra.Command{
+	Use:   "middleware <" + strings.Join(addableMiddlewares(), "|") + ">...",
+	Short: "Add ready middlewares and register them in InitRouter",
+	Long: "Generate ready middlewares into the middlewares package and register them in routes.InitRouter. " +
+		"Whatever the order they are added in, they run as " + strings.Join(middlewareNames(), ", ") + ", the first being the outermost.",
+	Args:      cobra.MinimumNArgs(1),
+	ValidArgs: addableMiddlewares(),
+	Run: func(cmd *cobra.Command, args []string) {
+		moduleName, _ := getModuleName()
+		data := newTemplateData(moduleName)
+
+		for _, name := range args {
+			m, err := lookupMiddleware(name)
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			if data.Router == "fiber" && m.name == "timeout" {
+				fatalf("❌ fiber cannot run net/http middlewares around its handlers, bound the request time with SERVER_WRITE_TIMEOUT instead")
+			}
+
+			// fiber recovers with its own middleware, the others get Recover
+			if m.template != "" && !(data.Router == "fiber" && m.name == "recover") {
+				path := filepath.Join(data.Layers.Middlewares.Dir, m.name+".go")
+				if err := generateMiddlewareFile(m.name, path, m.template, data); err != nil {
+					fatalf("❌ %v", err)
+				}
+			}
+			if err := requireModules(m.modules...); err != nil {
+				fatalf("❌ Failed to update go.mod: %v", err)
+			}
+
+			added, err := injectMiddleware(m)
+			switch {
+			case err != nil:
+				log.Printf("❌ Failed to register %s: %v", m.name, err)
+			case added:
+				fmt.Printf("✅ Registered %s in %s\n", m.name, routerFilePath())
+			default:
+				fmt.Printf("ℹ️  %s is already registered in %s\n", m.name, routerFilePath())
+			}
+			if err == nil && m.name == "jwt" {
+				allowMountedPublicPaths()
+			}
+		}
+	},
+}
+
+// allowMountedPublicPaths makes the auth endpoints and docs InitRouter
+// already mounts public, so that signing in needs no token.
+func allowMountedPublicPaths() {
+	paths, err := mountedPublicPaths()
+	if err != nil {
+		log.Printf("❌ Failed to read the public endpoints: %v", err)
+		return
+	}
+	for _, p := range paths {
+		if err := allowPublicPath(p); err != nil {
+			log.Printf("❌ Failed to make %s public: %v", p, err)
+		}
+	}
+}
+
+// generateMiddlewareFile renders a middleware template to path and records it
+// under the component name.
+func generateMiddlewareFile(name, path, tmpl string, data types.TemplateData) error {
+	written, err := generateFile("middleware", name, path, tmpl, data)
+	if written {
+		fmt.Println("✅ Middleware created at:", path)
+	}
+	return err
+}
+
+// standardMiddleware is a middleware add middleware generates and registers.
+type standardMiddleware struct {
+	name     string
+	template string // "" when another command generates it
+	fn       string // function registered in InitRouter, e.g. CORS
+	// args are the arguments the registration passes, "" for a plain
+	// func(http.Handler) http.Handler. {mw} stands for the middlewares
+	// package and {deps} for the InitRouter dependencies.
+	args    string
+	imports []string // packages args refer to
+	modules []string
+}
+
+// standardMiddlewares lists the middlewares in the order they run in,
+// outermost first: the request id is known to everything logged, panics are
+// logged as failed requests, preflights are answered before rate limiting and
+// authentication.
+var standardMiddlewares = []standardMiddleware{
+	{name: "requestid", template: "middleware_requestid.tmpl", fn: "RequestID"},
+	{name: "logging"}, // generated by init
+	{name: "recover", template: "middleware_recover.tmpl", fn: "Recover"},
+	{name: "cors", template: "middleware_cors.tmpl", fn: "CORS", args: `{mw}.CORSOptions{AllowedOrigins: []string{"*"}}`},
+	{name: "ratelimit", template: "middleware_ratelimit.tmpl", fn: "RateLimit", args: "100, time.Minute", imports: []string{"time"}},
+	{name: "timeout", template: "middleware_timeout.tmpl", fn: "Timeout", args: "10 * time.Second", imports: []string{"time"}},
+	{name: "jwt", template: "middleware_jwt.tmpl", fn: "JWT", args: `{mw}.JWTOptions{Secret: []byte({deps}.Config.JWT.Secret), Public: []string{"/"}}`, modules: []string{"github.com/golang-jwt/jwt/v5"}},
+}
+
+// fiberRecoverImport is the fiber middleware registered for recover.
+const fiberRecoverImport = "github.com/gofiber/fiber/v2/middleware/recover"
+
+func lookupMiddleware(name string) (standardMiddleware, error) {
+	for _, m := range standardMiddlewares {
+		if m.name == name && m.template != "" {
+			return m, nil
+		}
+	}
+	return standardMiddleware{}, fmt.Errorf("unknown middleware %q (use %s)", name, strings.Join(addableMiddlewares(), ", "))
+}
+
+func middlewareNames() []string {
+	names := make([]string, len(standardMiddlewares))
+	for i, m := range standardMiddlewares {
+		names[i] = m.name
+	}
+	return names
+}
+
+func addableMiddlewares() []string {
+	var names []string
+	for _, m := range standardMiddlewares {
+		if m.template != "" {
+			names = append(names, m.name)
+		}
+	}
+	return names
+}
+
+// injectMiddleware registers m in InitRouter among the middlewares already
+// registered, following the order of standardMiddlewares. It reports false
+// when m is registered already.
+//
+// chi, echo, gin and fiber get a Use statement, e.g. r.Use(middlewares.RequestID);
+// stdlib gets an argument of the chain(mux, ...) call InitRouter returns.
+func injectMiddleware(m standardMiddleware) (bool, error) {
+	framework := projectConfig().Router
+	rf, err := lookupRouter(framework)
+	if err != nil {
+		return false, err
+	}
+	module, err := getModuleName()
+	if err != nil {
+		return false, fmt.Errorf("failed to get module name: %w", err)
+	}
+	layers := projectConfig().templateLayers(module)
+
+	routerPath := routerFilePath()
+	src, err := readFile(routerPath)
+	if err != nil {
+		return false, err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return false, err
+	}
+
+	fn := s.findFunc("InitRouter")
+	if fn == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return false, fmt.Errorf("%s: expected a `func InitRouter(deps *bootstrap.Dependencies)` function", routerPath)
+	}
+	deps := fn.Type.Params.List[0].Names[0].Name
+	assign := routerAssign(fn, rf.constructors)
+	if assign == nil {
+		return false, fmt.Errorf("%s: InitRouter must create its router with `%s()`", routerPath, rf.constructors[0])
+	}
+	router := assign.Lhs[0].(*ast.Ident).Name
+
+	// Rank what is registered already by the functions it calls
+	ranks := make(map[string]int)
+	mwPkg := s.importName(layers.Middlewares.Import, layers.Middlewares.Package)
+	logPkg := s.importName(layers.Logging.Import, layers.Logging.Package)
+	for i, sm := range standardMiddlewares {
+		if sm.fn != "" && mwPkg != "" {
+			ranks[mwPkg+"."+sm.fn] = i
+		}
+	}
+	if logPkg != "" {
+		ranks[logPkg+".Middleware"] = slices.Index(middlewareNames(), "logging")
+		ranks[logPkg+".FiberMiddleware"] = slices.Index(middlewareNames(), "logging")
+	}
+	if recoverPkg := s.importName(fiberRecoverImport, "recover"); recoverPkg != "" {
+		ranks[recoverPkg+".New"] = slices.Index(middlewareNames(), "recover")
+	}
+	rank := slices.Index(middlewareNames(), m.name)
+
+	var slots []ast.Node
+	var chainCall *ast.CallExpr
+	if framework == "stdlib" {
+		if ret, ok := fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			if call, ok := ret.Results[0].(*ast.CallExpr); ok {
+				if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "chain" && len(call.Args) > 0 {
+					chainCall = call
+				}
+			}
+		}
+		if chainCall == nil {
+			return false, fmt.Errorf("%s: InitRouter must end with `return chain(%s, ...)`", routerPath, router)
+		}
+		for _, arg := range chainCall.Args[1:] {
+			slots = append(slots, arg)
+		}
+	} else {
+		for _, stmt := range fn.Body.List {
+			if isUseCall(stmt, router) {
+				slots = append(slots, stmt)
+			}
+		}
+	}
+
+	var before ast.Node
+	for _, slot := range slots {
+		r, known := middlewareRank(slot, ranks)
+		if known && r == rank {
+			return false, nil
+		}
+		if known && r > rank && before == nil {
+			before = slot
+		}
+	}
+
+	// Build the registration
+	expr := ""
+	if framework == "fiber" && m.name == "recover" {
+		expr = s.ensureImport(fiberRecoverImport, "fiberrecover") + ".New()"
+	} else {
+		mwPkg = s.ensureImport(layers.Middlewares.Import, layers.Middlewares.Package)
+		expr = mwPkg + "." + m.fn
+		if m.args != "" {
+			expr += "(" + strings.NewReplacer("{mw}", mwPkg, "{deps}", deps).Replace(m.args) + ")"
+		}
+		for _, imp := range m.imports {
+			s.ensureImport(imp, importBase(imp))
+		}
+		switch framework {
+		case "echo":
+			expr = s.ensureImport("github.com/labstack/echo/v4", "echo") + ".WrapMiddleware(" + expr + ")"
+		case "gin":
+			expr = s.ensureImport(layers.Request.Import, layers.Request.Package) + ".GinMiddleware(" + expr + ")"
+		case "fiber":
+			expr = s.ensureImport("github.com/gofiber/fiber/v2/middleware/adaptor", "adaptor") + ".HTTPMiddleware(" + expr + ")"
+		}
+	}
+
+	switch {
+	case framework == "stdlib" && before != nil:
+		s.insert(before.Pos(), expr+",\n\t\t")
+	case framework == "stdlib":
+		var last ast.Node = chainCall.Args[len(chainCall.Args)-1]
+		s.insertLine(chainCall.Rparen, last, expr+",", true)
+	case before != nil:
+		s.insert(before.Pos(), router+".Use("+expr+")\n\t")
+	case len(slots) > 0:
+		s.insert(slots[len(slots)-1].End(), "\n\t"+router+".Use("+expr+")")
+	default:
+		s.insert(assign.End(), "\n\t"+router+".Use("+expr+")")
+	}
+
+	out, err := s.bytes()
+	if err != nil {
+		return false, err
+	}
+	return true, writeTracked(routerPath, out)
+}
+
+// isUseCall reports whether stmt is router.Use(...).
+func isUseCall(stmt ast.Stmt, router string) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Use" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == router
+}
+
+// middlewareRank returns the position in standardMiddlewares of the first
+// known function node refers to, e.g. middlewares.CORS.
+func middlewareRank(node ast.Node, ranks map[string]int) (int, bool) {
+	rank, known := 0, false
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || known {
+			return !known
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			rank, known = ranks[x.Name+"."+sel.Sel.Name]
+		}
+		return !known
+	})
+	return rank, known
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+// migrationVersionLayout is the timestamp prefixing migration files, e.g.
+// 20250102150405_create_users.up.sql.
+const migrationVersionLayout = "20060102150405"
+
+// fromModels is bound to create migration --from-models.
+var fromModels bool
+
+var MigrationCmd = &cobra.Command{
+	Use:   "migration [name]",
+	Short: "Create timestamped up and down SQL migration files",
+	Long: "Create <version>_<name>.up.sql and .down.sql in the migrations directory, applied with `go run ./cmd migrate up`. " +
+		"With --from-models the project drafts them by comparing the GORM models with the database, so the database must be reachable.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		if len(n.Parents) > 0 {
+			fatalf("❌ Migrations share one directory, %q cannot be nested", n.Raw)
+		}
+		dir := migrationsDir()
+
+		if fromModels {
+			if err := writeModelRegistry(); err != nil {
+				fatalf("❌ %v", err)
+			}
+			draft := exec.Command("go", "run", "./cmd", "migrate", "draft", "-dir", dir, n.Snake)
+			if DryRun {
+				fmt.Println("ℹ️  Would run:", strings.Join(draft.Args, " "))
+				return
+			}
+			draft.Stdout, draft.Stderr = os.Stdout, os.Stderr
+			if err := draft.Run(); err != nil {
+				fatalf("❌ Failed to draft the migration: %v", err)
+			}
+			return
+		}
+
+		moduleName, _ := getModuleName()
+		data := newComponentData(moduleName, n)
+		base := time.Now().UTC().Format(migrationVersionLayout) + "_" + n.Snake
+		files := []struct{ path, tmpl string }{
+			{filepath.Join(dir, base+".up.sql"), "migration_up.tmpl"},
+			{filepath.Join(dir, base+".down.sql"), "migration_down.tmpl"},
+		}
+		for _, f := range files {
+			written, err := generateFile("migration", n.Snake, f.path, f.tmpl, data)
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			if written {
+				fmt.Println("✅ Migration created at:", f.path)
+			}
+		}
+	},
+}
+
+// migrationsDir holds the SQL migrations, embedded by its migrations package.
+func migrationsDir() string {
+	return filepath.Join(projectConfig().Layers.Database.Dir, "migrations")
+}
+
+// generateInitialMigration writes the migration creating the users table of
+// the User model, unless the project has one already.
+func generateInitialMigration(data types.TemplateData) error {
+	dir := migrationsDir()
+	names, err := listFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if strings.HasSuffix(name, "_create_users.up.sql") {
+			return nil
+		}
+	}
+
+	base := time.Now().UTC().Format(migrationVersionLayout) + "_create_users"
+	for path, tmpl := range map[string]string{
+		filepath.Join(dir, base+".up.sql"):   "migration_create_users_up.tmpl",
+		filepath.Join(dir, base+".down.sql"): "migration_create_users_down.tmpl",
+	} {
+		written, err := renderTemplate(path, tmpl, data)
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Printf("✅ Generated: %s\n", path)
+		}
+	}
+	return nil
+}
+
+// modelRegistryPath is the file listing every model for the migration draft.
+func modelRegistryPath() string {
+	return filepath.Join(projectConfig().Layers.Models.Dir, "registry.go")
+}
+
+// writeModelRegistry rewrites models.All() with every struct type the models
+// package declares.
+func writeModelRegistry() error {
+	pkg, err := readModels()
+	if err != nil {
+		return err
+	}
+
+	moduleName, _ := getModuleName()
+	data := newTemplateData(moduleName)
+	data.Models = pkg.names
+	_, err = generateFile("model", "registry", modelRegistryPath(), "model_registry.tmpl", data)
+	return err
+}
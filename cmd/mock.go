@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+)
+
+// testFileName turns a file name pattern into the name of its test, e.g.
+// {name}_usecase.go into order_usecase_test.go.
+func testFileName(pattern, name string) string {
+	return strings.TrimSuffix(fileName(pattern, name), ".go") + "_test.go"
+}
+
+// generateTest writes the test of a component next to it and records it with
+// the component, so destroy removes it too.
+func generateTest(kind string, n types.Name, path, tmpl string, data types.TemplateData) error {
+	written, err := generateFile(kind, componentName(n), path, tmpl, data)
+	if err != nil {
+		return err
+	}
+	if written {
+		fmt.Println("✅ Test created at:", path)
+	}
+	return nil
+}
+
+// generateMock writes a mock of the interface iface, read from the file at
+// interfacePath of the package importPath, into the mocks package. file is
+// the name of the mock file, e.g. order_repository.go.
+func generateMock(kind string, n types.Name, interfacePath, iface, importPath, alias, file string) error {
+	moduleName, _ := getModuleName()
+	data := newComponentData(moduleName, n)
+
+	mock, err := readMock(interfacePath, iface, importPath, alias, data.Layers)
+	if err != nil {
+		return err
+	}
+	data.Mock = mock
+
+	path := filepath.Join(data.Layers.Mocks.Dir, file)
+	written, err := generateFile(kind, componentName(n), path, "mock.tmpl", data)
+	if err != nil {
+		return err
+	}
+	if written {
+		fmt.Println("✅ Mock created at:", path)
+	}
+	return nil
+}
+
+// readMock describes the mock of the interface iface declared in the file at
+// path. Types of the interface package are qualified with alias.
+func readMock(path, iface, importPath, alias string, layers types.Layers) (types.Mock, error) {
+	mock := types.Mock{Name: iface, Interface: alias + "." + iface}
+
+	src, err := readFile(path)
+	if err != nil {
+		return mock, err
+	}
+	s, err := parseGoSource(path, src)
+	if err != nil {
+		return mock, err
+	}
+
+	var decl *ast.InterfaceType
+	ast.Inspect(s.file, func(node ast.Node) bool {
+		if ts, ok := node.(*ast.TypeSpec); ok && ts.Name.Name == iface {
+			decl, _ = ts.Type.(*ast.InterfaceType)
+			return false
+		}
+		return decl == nil
+	})
+	if decl == nil {
+		return mock, fmt.Errorf("%s declares no %s interface", path, iface)
+	}
+
+	// The identifier each import is used with in the file
+	known := map[string]string{
+		layers.Models.Import:       layers.Models.Package,
+		layers.Repositories.Import: layers.Repositories.Package,
+		layers.Usecases.Import:     layers.Usecases.Package,
+		layers.Config.Import:       layers.Config.Package,
+		layers.Services.Import:     layers.Services.Package,
+	}
+	imports := make(map[string]string) // identifier to path
+	for _, imp := range s.file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		switch {
+		case imp.Name != nil:
+			imports[imp.Name.Name] = p
+		case known[p] != "":
+			imports[known[p]] = p
+		default:
+			imports[packageName(p)] = p
+		}
+	}
+
+	used := map[string]bool{alias: true}
+	qualify := func(name string) string {
+		if ast.IsExported(name) && gotypes.Universe.Lookup(name) == nil {
+			return alias + "." + name
+		}
+		return name
+	}
+	typeOf := func(expr ast.Expr) string {
+		return typeString(expr, qualify, used)
+	}
+
+	for _, field := range decl.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return mock, fmt.Errorf("%s embeds %s, write its mock by hand", iface, gotypes.ExprString(field.Type))
+		}
+
+		method := types.MockMethod{Name: field.Names[0].Name}
+		var params, args, results []string
+		taken := make(map[string]bool)
+		for i, p := range fn.Params.List {
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(i))}
+			}
+			for _, name := range names {
+				argName := name.Name
+				if argName == "_" || argName == "m" || taken[argName] {
+					argName = "arg" + strconv.Itoa(len(params))
+				}
+				taken[argName] = true
+				params = append(params, argName+" "+typeOf(p.Type))
+				if _, variadic := p.Type.(*ast.Ellipsis); variadic {
+					argName += "..."
+				}
+				args = append(args, argName)
+			}
+		}
+		if fn.Results != nil {
+			for _, r := range fn.Results.List {
+				for range max(len(r.Names), 1) {
+					results = append(results, typeOf(r.Type))
+				}
+			}
+		}
+
+		method.Params = strings.Join(params, ", ")
+		method.Args = strings.Join(args, ", ")
+		switch len(results) {
+		case 0:
+		case 1:
+			method.Results = results[0]
+		default:
+			method.Results = "(" + strings.Join(results, ", ") + ")"
+		}
+		mock.Methods = append(mock.Methods, method)
+	}
+
+	mock.Imports = append(mock.Imports, alias+" "+strconv.Quote(importPath))
+	for name := range used {
+		p, ok := imports[name]
+		if name == alias || !ok || p == "sync" {
+			continue
+		}
+		spec := strconv.Quote(p)
+		if name != packageName(p) {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			mock.Imports = append(mock.Imports, spec)
+		} else {
+			mock.StdImports = append(mock.StdImports, spec)
+		}
+	}
+	mock.StdImports = append(mock.StdImports, strconv.Quote("sync"))
+	slices.Sort(mock.StdImports)
+	slices.Sort(mock.Imports)
+	return mock, nil
+}
+
+// typeString prints a type expression, qualifying the identifiers of the
+// interface package with qualify and noting the packages it refers to.
+func typeString(expr ast.Expr, qualify func(string) string, used map[string]bool) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return qualify(e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			used[x.Name] = true
+		}
+		return gotypes.ExprString(e)
+	case *ast.StarExpr:
+		return "*" + typeString(e.X, qualify, used)
+	case *ast.Ellipsis:
+		return "..." + typeString(e.Elt, qualify, used)
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + typeString(e.Elt, qualify, used)
+		}
+		return "[" + gotypes.ExprString(e.Len) + "]" + typeString(e.Elt, qualify, used)
+	case *ast.MapType:
+		return "map[" + typeString(e.Key, qualify, used) + "]" + typeString(e.Value, qualify, used)
+	case *ast.ChanType:
+		elt := typeString(e.Value, qualify, used)
+		switch e.Dir {
+		case ast.SEND:
+			return "chan<- " + elt
+		case ast.RECV:
+			return "<-chan " + elt
+		}
+		return "chan " + elt
+	case *ast.FuncType:
+		var params, results []string
+		for _, p := range e.Params.List {
+			for range max(len(p.Names), 1) {
+				params = append(params, typeString(p.Type, qualify, used))
+			}
+		}
+		if e.Results != nil {
+			for _, r := range e.Results.List {
+				for range max(len(r.Names), 1) {
+					results = append(results, typeString(r.Type, qualify, used))
+				}
+			}
+		}
+		out := "func(" + strings.Join(params, ", ") + ")"
+		if len(results) == 1 {
+			return out + " " + results[0]
+		}
+		if len(results) > 1 {
+			out += " (" + strings.Join(results, ", ") + ")"
+		}
+		return out
+	case *ast.IndexExpr:
+		return typeString(e.X, qualify, used) + "[" + typeString(e.Index, qualify, used) + "]"
+	}
+	// e.g. interface{} and struct{}
+	return gotypes.ExprString(expr)
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName guesses the name of the package at path, e.g. chi for
+// github.com/go-chi/chi/v5.
+func packageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if majorVersion.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var ModelCmd = &cobra.Command{
+	Use:   "model [name]",
+	Short: "Create a new GORM model from a field specification",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+
+		fields := templateFields()
+		if len(fields) == 0 {
+			fatalf("❌ A model needs --fields, e.g. --fields \"name:string,price:decimal\"")
+		}
+
+		cfg := projectConfig()
+		if err := makeDir(cfg.Layers.Models.Dir); err != nil {
+			fatalf("❌ Failed to create models directory: %v", err)
+		}
+
+		moduleName, _ := getModuleName()
+
+		templateData := newComponentData(moduleName, n)
+		templateData.Fields = fields
+
+		content, err := executeTemplate("model.tmpl", templateData)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+
+		outputPath := filepath.Join(cfg.Layers.Models.Dir, fileName(cfg.Files.Model, n.Snake))
+		written, err := writeGenerated(outputPath, content)
+		if err != nil {
+			fatalf("❌ Failed to write model file: %v", err)
+		}
+		if written {
+			recordGenerated("model", componentName(n), outputPath, "model.tmpl", content)
+			fmt.Println("✅ Model created at:", outputPath)
+		}
+		if err := writeModelRegistry(); err != nil {
+			log.Printf("❌ Failed to update %s: %v", modelRegistryPath(), err)
+		}
+	},
+}
+
+// modelPackage is the models package as gostart reads it.
+type modelPackage struct {
+	structs map[string]*ast.StructType // exported struct types by name
+	names   []string                   // their sorted names, e.g. [Order User]
+	consts  []string                   // exported constants, e.g. OrderStatusPaid
+}
+
+// readModels parses the files of the models directory.
+func readModels() (*modelPackage, error) {
+	dir := projectConfig().Layers.Models.Dir
+	names, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &modelPackage{structs: make(map[string]*ast.StructType)}
+	fset := token.NewFileSet()
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := readFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, name), err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if st, ok := spec.Type.(*ast.StructType); ok && spec.Name.IsExported() {
+						pkg.structs[spec.Name.Name] = st
+						pkg.names = append(pkg.names, spec.Name.Name)
+					}
+				case *ast.ValueSpec:
+					if gen.Tok != token.CONST {
+						continue
+					}
+					for _, id := range spec.Names {
+						if id.IsExported() {
+							pkg.consts = append(pkg.consts, id.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(pkg.names)
+	return pkg, nil
+}
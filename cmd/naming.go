@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+)
+
+// commonInitialisms are written in upper case inside Go identifiers.
+var commonInitialisms = map[string]bool{
+	"api": true, "id": true, "ip": true, "json": true, "sql": true,
+	"url": true, "uri": true, "uuid": true, "http": true, "html": true,
+}
+
+// reservedNames are packages and locals the generated code uses next to
+// component variables, so a component with that camelCase name would shadow
+// or clash with them.
+var reservedNames = map[string]bool{
+	"context": true, "errors": true, "fmt": true, "http": true, "time": true,
+	"slices": true, "slog": true, "gorm": true, "chi": true, "models": true,
+	"request": true, "response": true, "usecases": true, "repositories": true,
+	"ctx": true, "err": true, "id": true, "req": true, "repo": true, "db": true, "logger": true,
+	"r": true, "w": true, "h": true, "u": true, "t": true,
+}
+
+// parseName turns a component name given on the command line into every
+// form the generators need. It accepts snake_case, kebab-case, camelCase and
+// PascalCase words, optionally nested under parent paths: shop/order_item.
+func parseName(input string) (types.Name, error) {
+	segments := strings.Split(strings.Trim(input, "/"), "/")
+
+	var parents []string
+	for _, segment := range segments[:len(segments)-1] {
+		words, err := nameWords(segment)
+		if err != nil {
+			return types.Name{}, err
+		}
+		pkg := strings.Join(words, "")
+		if err := checkIdentifier(segment, pkg); err != nil {
+			return types.Name{}, err
+		}
+		parents = append(parents, pkg)
+	}
+
+	words, err := nameWords(segments[len(segments)-1])
+	if err != nil {
+		return types.Name{}, err
+	}
+	plural := append(append([]string{}, words[:len(words)-1]...), pluralize(words[len(words)-1]))
+
+	n := types.Name{
+		Raw:          input,
+		Words:        words,
+		Pascal:       pascalWords(words),
+		Camel:        camelWords(words),
+		Snake:        strings.Join(words, "_"),
+		Kebab:        strings.Join(words, "-"),
+		Human:        strings.Join(words, " "),
+		PluralPascal: pascalWords(plural),
+		PluralCamel:  camelWords(plural),
+		PluralSnake:  strings.Join(plural, "_"),
+		PluralKebab:  strings.Join(plural, "-"),
+		PluralHuman:  strings.Join(plural, " "),
+		Package:      strings.Join(words, ""),
+		Parents:      parents,
+	}
+	n.Dir = strings.Join(append(append([]string{}, parents...), n.Package), "/")
+
+	if err := checkIdentifier(input, n.Package); err != nil {
+		return n, err
+	}
+	for _, ident := range []string{n.Camel, n.PluralCamel} {
+		if err := checkIdentifier(input, ident); err != nil {
+			return n, err
+		}
+		if reservedNames[ident] {
+			return n, fmt.Errorf("name %q would shadow the %s package in generated code, choose another name", input, ident)
+		}
+	}
+	return n, nil
+}
+
+// mustParseName parses a component name or exits with the reason.
+func mustParseName(input string) types.Name {
+	n, err := parseName(input)
+	if err != nil {
+		fatalf("❌ Invalid name: %v", err)
+	}
+	return n
+}
+
+// mustParseComponentName parses the name of a component aliased in the
+// usecases and repositories indexes, exiting when it is invalid or taken.
+func mustParseComponentName(input string) types.Name {
+	n := mustParseName(input)
+	if err := checkIndexedName(n); err != nil {
+		fatalf("❌ %v", err)
+	}
+	return n
+}
+
+// nameWords splits a name into lower-case words on _, -, spaces and case
+// changes: OrderItem, orderItem, order_item and order-item all give
+// [order item], and HTTPServer gives [http server].
+func nameWords(s string) ([]string, error) {
+	if s == "" {
+		return nil, fmt.Errorf("name must not be empty")
+	}
+
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return nil, fmt.Errorf("name %q may only contain ASCII letters, digits, _ and -", s)
+		case unicode.IsUpper(r):
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			prevUpper := i > 0 && unicode.IsUpper(runes[i-1])
+			if prevLower || (prevUpper && nextLower) {
+				flush()
+			}
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("name %q has no letters", s)
+	}
+	return words, nil
+}
+
+// checkIdentifier rejects identifiers Go would not accept.
+func checkIdentifier(input, ident string) error {
+	if token.IsKeyword(ident) {
+		return fmt.Errorf("name %q is a Go keyword (%q), choose another name", input, ident)
+	}
+	if !token.IsIdentifier(ident) {
+		return fmt.Errorf("name %q does not give a valid Go identifier (%q), it must start with a letter", input, ident)
+	}
+	return nil
+}
+
+func pascalWords(words []string) string {
+	var buf strings.Builder
+	for _, word := range words {
+		if commonInitialisms[word] {
+			buf.WriteString(strings.ToUpper(word))
+			continue
+		}
+		buf.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return buf.String()
+}
+
+func camelWords(words []string) string {
+	return words[0] + pascalWords(words[1:])
+}
+
+// snakeToPascal turns customer_id into CustomerID.
+func snakeToPascal(s string) string {
+	var words []string
+	for _, word := range strings.Split(s, "_") {
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return pascalWords(words)
+}
+
+// pluralize returns the English plural of a lower-case word.
+func pluralize(word string) string {
+	switch {
+	case word == "":
+		return word
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		input                       string
+		pascal, camel, snake, kebab string
+		pluralSnake, pkg, dir       string
+		parents                     []string
+	}{
+		{"order_item", "OrderItem", "orderItem", "order_item", "order-item", "order_items", "orderitem", "orderitem", nil},
+		{"OrderItem", "OrderItem", "orderItem", "order_item", "order-item", "order_items", "orderitem", "orderitem", nil},
+		{"orderItem", "OrderItem", "orderItem", "order_item", "order-item", "order_items", "orderitem", "orderitem", nil},
+		{"order-item", "OrderItem", "orderItem", "order_item", "order-item", "order_items", "orderitem", "orderitem", nil},
+		{"HTTPServer", "HTTPServer", "httpServer", "http_server", "http-server", "http_servers", "httpserver", "httpserver", nil},
+		{"api_key", "APIKey", "apiKey", "api_key", "api-key", "api_keys", "apikey", "apikey", nil},
+		{"category", "Category", "category", "category", "category", "categories", "category", "category", nil},
+		{"box", "Box", "box", "box", "box", "boxes", "box", "box", nil},
+		{"shop/order_item", "OrderItem", "orderItem", "order_item", "order-item", "order_items", "orderitem", "shop/orderitem", []string{"shop"}},
+		{"back-office/user_role/", "UserRole", "userRole", "user_role", "user-role", "user_roles", "userrole", "backoffice/userrole", []string{"backoffice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			n, err := parseName(tt.input)
+			if err != nil {
+				t.Fatalf("parseName(%q): %v", tt.input, err)
+			}
+			got := []string{n.Pascal, n.Camel, n.Snake, n.Kebab, n.PluralSnake, n.Package, n.Dir}
+			want := []string{tt.pascal, tt.camel, tt.snake, tt.kebab, tt.pluralSnake, tt.pkg, tt.dir}
+			if !slices.Equal(got, want) {
+				t.Errorf("parseName(%q) = %q, want %q", tt.input, got, want)
+			}
+			if !slices.Equal(n.Parents, tt.parents) {
+				t.Errorf("parseName(%q).Parents = %q, want %q", tt.input, n.Parents, tt.parents)
+			}
+		})
+	}
+}
+
+func TestParseNameErrors(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"", "must not be empty"},
+		{"__", "has no letters"},
+		{"order.item", "may only contain"},
+		{"café", "may only contain"},
+		{"1order", "must start with a letter"},
+		{"9shop/order", "must start with a letter"},
+		{"func", "Go keyword"},
+		{"context", "would shadow"},
+		{"err", "would shadow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseName(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseName(%q) error = %v, want it to contain %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
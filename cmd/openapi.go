@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"log"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// docsMountPath is where the docs package serves Swagger UI.
+const docsMountPath = "/docs"
+
+var OpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Generate the OpenAPI spec of the API from its routes, request DTOs and responses",
+	Long: "Load the project and follow InitRouter to every route the handlers register. Each endpoint is described from its body: " +
+		"the request DTO it parses and the validate tags of its fields, its path and query parameters, the pagination options " +
+		"of list endpoints and the response helpers it answers with, wrapped in the APIResponse envelope. " +
+		"With --docs the spec is also embedded in a docs package serving Swagger UI at " + docsMountPath + ".",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		moduleName, err := getModuleName()
+		if err != nil {
+			fatalf("❌ Failed to get module name from go.mod: %v", err)
+		}
+		output, _ := cmd.Flags().GetString("output")
+		title, _ := cmd.Flags().GetString("title")
+		version, _ := cmd.Flags().GetString("api-version")
+		docs, _ := cmd.Flags().GetBool("docs")
+		if title == "" {
+			title = path.Base(moduleName)
+		}
+
+		data := newTemplateData(moduleName)
+		log.Println("🚀 Generating the OpenAPI spec")
+		spec, err := buildOpenAPI(data, openAPIInfo{Title: title, Version: version})
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		content, err := marshalOpenAPI(spec)
+		if err != nil {
+			fatalf("❌ Failed to encode the spec: %v", err)
+		}
+		writeSpec(output, content)
+
+		docsPath := filepath.Join(data.Layers.Docs.Dir, "docs.go")
+		if !docs && !fileExists(docsPath) {
+			return
+		}
+		// The docs package embeds its own copy of the spec
+		writeSpec(filepath.Join(data.Layers.Docs.Dir, "openapi.yaml"), content)
+		ensureDocs(data)
+		if err := injectDocsRoute(data); err != nil {
+			log.Printf("❌ Failed to register the docs route: %v", err)
+		}
+		if err := allowPublicPath(docsMountPath + "*"); err != nil {
+			log.Printf("❌ Failed to make the docs public: %v", err)
+		}
+	},
+}
+
+func init() {
+	OpenAPICmd.Flags().StringP("output", "o", "openapi.yaml", "File to write the spec to")
+	OpenAPICmd.Flags().String("title", "", "Title of the API (default: the last element of the module path)")
+	OpenAPICmd.Flags().String("api-version", "1.0.0", "Version of the API")
+	OpenAPICmd.Flags().Bool("docs", false, "Serve the spec with Swagger UI at "+docsMountPath)
+}
+
+func marshalOpenAPI(spec *openAPISpec) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(spec); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeSpec(p string, content []byte) {
+	if err := makeDir(filepath.Dir(p)); err != nil {
+		fatalf("❌ Failed to create %s: %v", filepath.Dir(p), err)
+	}
+	written, err := writeGenerated(p, content)
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	if written {
+		recordGenerated("openapi", "openapi", p, "", content)
+		fmt.Println("✅ Written:", p)
+	}
+}
+
+// ensureDocs generates the docs package serving Swagger UI.
+func ensureDocs(data types.TemplateData) {
+	p := filepath.Join(data.Layers.Docs.Dir, "docs.go")
+	if fileExists(p) {
+		return
+	}
+	written, err := generateFile("docs", "docs", p, "docs.tmpl", data)
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	if written {
+		fmt.Println("✅ Created:", p)
+	}
+}
+
+// injectDocsRoute serves the docs package at /docs from InitRouter, e.g.
+// r.Mount("/docs", docs.Handler("/docs")) with chi.
+func injectDocsRoute(data types.TemplateData) error {
+	framework := projectConfig().Router
+	r, err := lookupRouter(framework)
+	if err != nil {
+		return err
+	}
+
+	routerPath := routerFilePath()
+	src, err := readFile(routerPath)
+	if err != nil {
+		return err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return err
+	}
+	fn := s.findFunc("InitRouter")
+	if fn == nil {
+		return fmt.Errorf("%s: expected a `func InitRouter(deps *bootstrap.Dependencies) http.Handler` function", routerPath)
+	}
+	router := routerVar(fn, r.constructors)
+	if router == "" {
+		return fmt.Errorf("%s: InitRouter must create its router with `%s()`", routerPath, r.constructors[0])
+	}
+	ret, ok := fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
+	if !ok {
+		return fmt.Errorf("%s: InitRouter must end with `return %s`", routerPath, router)
+	}
+
+	if pkg := s.importName(data.Layers.Docs.Import, data.Layers.Docs.Package); pkg != "" {
+		mounted := false
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "Handler" {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg {
+					mounted = true
+				}
+			}
+			return !mounted
+		})
+		if mounted {
+			return nil
+		}
+	}
+
+	docs := s.ensureImport(data.Layers.Docs.Import, data.Layers.Docs.Package)
+	handler := fmt.Sprintf("%s.Handler(%s)", docs, strconv.Quote(docsMountPath))
+	var stmt string
+	switch framework {
+	case "chi":
+		stmt = fmt.Sprintf("%s.Mount(%s, %s)", router, strconv.Quote(docsMountPath), handler)
+	case "stdlib":
+		stmt = fmt.Sprintf("%s.Handle(%s, %s)", router, strconv.Quote("GET "+docsMountPath+"/"), handler)
+	case "echo":
+		echo := s.ensureImport("github.com/labstack/echo/v4", "echo")
+		stmt = fmt.Sprintf("%s.GET(%s, %s.WrapHandler(%s))", router, strconv.Quote(docsMountPath+"*"), echo, handler)
+	case "gin":
+		gin := s.ensureImport("github.com/gin-gonic/gin", "gin")
+		stmt = fmt.Sprintf("%s.GET(%s, %s.WrapH(%s))", router, strconv.Quote(docsMountPath+"/*path"), gin, handler)
+	case "fiber":
+		adaptor := s.ensureImport("github.com/gofiber/fiber/v2/middleware/adaptor", "adaptor")
+		stmt = fmt.Sprintf("%s.Get(%s, %s.HTTPHandler(%s))", router, strconv.Quote(docsMountPath+"*"), adaptor, handler)
+	}
+	s.insert(ret.Pos(), stmt+"\n\n\t")
+
+	out, err := s.bytes()
+	if err != nil {
+		return err
+	}
+	if err := writeTracked(routerPath, out); err != nil {
+		return err
+	}
+	fmt.Println("✅ Docs served at", docsMountPath)
+	return nil
+}
@@ -0,0 +1,865 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// openAPIBuilder reads the routes of a loaded project into a spec. Routes are
+// found from InitRouter: the handlers it mounts, such as
+// r.Mount("/orders", deps.OrderHandler.Routes()) or
+// deps.OrderHandler.Routes(e.Group("/orders")), and the endpoints their
+// Routes method registers. The body of each endpoint tells its request DTO,
+// path and query parameters and the response helpers it answers with.
+type openAPIBuilder struct {
+	layers types.Layers
+	spec   *openAPISpec
+
+	funcs    map[*gotypes.Func]funcSource // declaration of every project function
+	vars     map[gotypes.Object]varSource // initializer of every package-level variable
+	prefixes map[gotypes.Object]string    // path of each route group variable
+	response *gotypes.Package
+	paginate *gotypes.Package
+	guarded  bool     // whether InitRouter applies middlewares.JWT to every route
+	public   []string // paths the JWT middleware lets through, e.g. /auth/*
+
+	schemaNames map[*gotypes.TypeName]string
+	schemaTypes map[string]*gotypes.TypeName
+	operations  map[string]bool
+}
+
+// funcSource is a function declaration with the package it was loaded from.
+type funcSource struct {
+	decl *ast.FuncDecl
+	pkg  *packages.Package
+}
+
+// varSource is the initializer of a variable with the type information of
+// its package.
+type varSource struct {
+	value ast.Expr
+	info  *gotypes.Info
+}
+
+var httpMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions,
+}
+
+// buildOpenAPI loads the packages of the project in the working directory
+// and describes the routes InitRouter mounts.
+func buildOpenAPI(data types.TemplateData, info openAPIInfo) (*openAPISpec, error) {
+	// Dependencies are type-checked from source: the export data of a newer
+	// toolchain may be in a format this loader cannot read
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the packages: %w", err)
+	}
+	var errs []string
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, e := range p.Errors {
+			errs = append(errs, e.Error())
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("the project does not build:\n  %s", strings.Join(errs, "\n  "))
+	}
+
+	b := &openAPIBuilder{
+		layers: data.Layers,
+		spec: &openAPISpec{
+			OpenAPI:    "3.0.3",
+			Info:       info,
+			Components: openAPIComponents{Schemas: make(map[string]*schema)},
+		},
+		funcs:       make(map[*gotypes.Func]funcSource),
+		vars:        make(map[gotypes.Object]varSource),
+		prefixes:    make(map[gotypes.Object]string),
+		schemaNames: make(map[*gotypes.TypeName]string),
+		schemaTypes: make(map[string]*gotypes.TypeName),
+		operations:  make(map[string]bool),
+	}
+	var routes *packages.Package
+	for _, p := range pkgs {
+		b.index(p)
+		switch p.PkgPath {
+		case data.Layers.Routes.Import:
+			routes = p
+		case data.Layers.Response.Import:
+			b.response = p.Types
+		case data.Layers.Pagination.Import:
+			b.paginate = p.Types
+		}
+	}
+	if routes == nil {
+		return nil, fmt.Errorf("no package %s, run gostart openapi from the project root", data.Layers.Routes.Import)
+	}
+	fn, _ := routes.Types.Scope().Lookup("InitRouter").(*gotypes.Func)
+	init, ok := b.funcs[fn]
+	if fn == nil || !ok {
+		return nil, fmt.Errorf("%s declares no InitRouter function", data.Layers.Routes.Dir)
+	}
+
+	b.findJWT(init)
+	b.walk(init, init.decl.Body, "")
+	return b.spec, nil
+}
+
+// findJWT notes whether InitRouter guards the routes with the JWT middleware,
+// e.g. r.Use(middlewares.JWT(middlewares.JWTOptions{...})), and its public
+// paths.
+func (b *openAPIBuilder) findJWT(src funcSource) {
+	info := src.pkg.TypesInfo
+	ast.Inspect(src.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := typeutil.StaticCallee(info, call)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != b.layers.Middlewares.Import || fn.Name() != "JWT" {
+			return true
+		}
+		b.guarded = true
+		for _, arg := range call.Args {
+			if lit, litInfo := b.compositeLit(info, arg); lit != nil {
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Public" {
+							b.public = stringList(litInfo, kv.Value)
+						}
+					}
+				}
+			}
+		}
+		return false
+	})
+}
+
+// isPublic reports whether the JWT middleware lets requests to p through.
+func (b *openAPIBuilder) isPublic(p string) bool {
+	return slices.ContainsFunc(b.public, func(pattern string) bool {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			return strings.HasPrefix(p, prefix)
+		}
+		return p == pattern
+	})
+}
+
+// index notes the function declarations and package-level variables of p.
+func (b *openAPIBuilder) index(p *packages.Package) {
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if fn, ok := p.TypesInfo.Defs[decl.Name].(*gotypes.Func); ok && decl.Body != nil {
+					b.funcs[fn] = funcSource{decl: decl, pkg: p}
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if obj := p.TypesInfo.Defs[name]; obj != nil && i < len(vs.Values) {
+							b.vars[obj] = varSource{vs.Values[i], p.TypesInfo}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// walk finds the route groups, mounted handlers and routes in node, a part
+// of the body of src, whose routes live under prefix.
+func (b *openAPIBuilder) walk(src funcSource, node ast.Node, prefix string) {
+	info := src.pkg.TypesInfo
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// api := e.Group("/api")
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if p, ok := b.groupPath(info, n.Rhs[i], prefix); ok {
+					if obj := info.ObjectOf(id); obj != nil {
+						b.prefixes[obj] = p
+					}
+				}
+			}
+		case *ast.CallExpr:
+			return b.visitCall(src, n, prefix)
+		}
+		return true
+	})
+}
+
+// visitCall handles a call of a router function, reporting whether its
+// arguments still need walking.
+func (b *openAPIBuilder) visitCall(src funcSource, call *ast.CallExpr, prefix string) bool {
+	info := src.pkg.TypesInfo
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+
+	switch sel.Sel.Name {
+	case "Route":
+		// r.Route("/api", func(r chi.Router) { ... })
+		if len(call.Args) == 2 {
+			p, ok := stringValue(info, call.Args[0])
+			if lit, isLit := call.Args[1].(*ast.FuncLit); ok && isLit {
+				b.walk(src, lit.Body, joinPath(prefix, p))
+				return false
+			}
+		}
+	case "Mount":
+		// r.Mount("/orders", deps.OrderHandler.Routes())
+		if len(call.Args) == 2 {
+			p, ok := stringValue(info, call.Args[0])
+			if inner, isCall := call.Args[1].(*ast.CallExpr); ok && isCall {
+				if routes, found := b.routesMethod(info, inner); found {
+					b.walk(routes, routes.decl.Body, joinPath(prefix, p))
+					return false
+				}
+			}
+		}
+	case "Routes":
+		// deps.OrderHandler.Routes(mux, "/orders") or .Routes(e.Group("/orders"))
+		if routes, found := b.routesMethod(info, call); found {
+			p := prefix
+			for _, arg := range call.Args {
+				if group, ok := b.groupPath(info, arg, prefix); ok {
+					p = group
+				}
+			}
+			b.walk(routes, routes.decl.Body, p)
+			return false
+		}
+	}
+
+	if method, pattern, handler, ok := routeCall(info, call); ok {
+		b.addRoute(src, call, method, joinPath(prefix, pattern), handler)
+		return false
+	}
+	return true
+}
+
+// routesMethod returns the declaration of the Routes method call invokes.
+func (b *openAPIBuilder) routesMethod(info *gotypes.Info, call *ast.CallExpr) (funcSource, bool) {
+	fn := typeutil.StaticCallee(info, call)
+	if fn == nil || fn.Name() != "Routes" {
+		return funcSource{}, false
+	}
+	src, ok := b.funcs[fn]
+	return src, ok
+}
+
+// groupPath is the path of a route group expression such as
+// e.Group("/api"), a variable holding one, or a path string.
+func (b *openAPIBuilder) groupPath(info *gotypes.Info, expr ast.Expr, prefix string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Group" || len(e.Args) == 0 {
+			return "", false
+		}
+		p, ok := stringValue(info, e.Args[0])
+		if !ok {
+			return "", false
+		}
+		base, ok := b.groupPath(info, sel.X, prefix)
+		if !ok {
+			base = prefix
+		}
+		return joinPath(base, p), true
+	case *ast.Ident:
+		if p, ok := b.prefixes[info.ObjectOf(e)]; ok {
+			return p, true
+		}
+	}
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return joinPath(prefix, constant.StringVal(tv.Value)), true
+	}
+	return "", false
+}
+
+// routeCall recognizes the registration of a route: r.Get("/", h.List),
+// g.GET("/:id", request.Echo(h.FindByID)) or
+// mux.HandleFunc("GET "+prefix+"/{id}", h.FindByID).
+func routeCall(info *gotypes.Info, call *ast.CallExpr) (method, pattern string, handler ast.Expr, ok bool) {
+	sel, isSel := call.Fun.(*ast.SelectorExpr)
+	if !isSel || len(call.Args) < 2 {
+		return "", "", nil, false
+	}
+	name := sel.Sel.Name
+	args := call.Args
+
+	switch {
+	case slices.Contains(httpMethods, strings.ToUpper(name)):
+		pattern, ok = stringValue(info, args[0])
+		return strings.ToUpper(name), pattern, args[len(args)-1], ok
+	case (name == "Method" || name == "MethodFunc") && len(args) == 3:
+		method, ok = stringValue(info, args[0])
+		if !ok {
+			return "", "", nil, false
+		}
+		pattern, ok = stringValue(info, args[1])
+		return strings.ToUpper(method), pattern, args[2], ok
+	case name == "HandleFunc" || name == "Handle":
+		p, ok := stringValue(info, args[0])
+		if !ok {
+			return "", "", nil, false
+		}
+		// Patterns without a method serve every method, they are left out
+		method, pattern, found := strings.Cut(p, " ")
+		if !found || !slices.Contains(httpMethods, method) {
+			return "", "", nil, false
+		}
+		return method, strings.TrimSpace(pattern), args[1], true
+	}
+	return "", "", nil, false
+}
+
+// stringValue evaluates a string expression. Strings only known at run time,
+// such as the prefix parameter of Routes(mux, prefix), are empty: the mount
+// path stands for them.
+func stringValue(info *gotypes.Info, expr ast.Expr) (string, bool) {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		if tv.Value.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(tv.Value), true
+	}
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return stringValue(info, e.X)
+	case *ast.BinaryExpr:
+		x, okX := stringValue(info, e.X)
+		y, okY := stringValue(info, e.Y)
+		return x + y, e.Op == token.ADD && okX && okY
+	case *ast.Ident:
+		if t, ok := info.TypeOf(e).Underlying().(*gotypes.Basic); ok && t.Info()&gotypes.IsString != 0 {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+func joinPath(prefix, p string) string {
+	return path.Join("/", prefix, p)
+}
+
+var (
+	colonParam    = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+	wildcardParam = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(?:\.\.\.|:[^}]*)\}`)
+	pathParam     = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// openAPIPath turns a route pattern into an OpenAPI path and its parameters:
+// /orders/:id, /orders/{id} and /orders/{id:[0-9]+} all give /orders/{id}.
+func openAPIPath(pattern string) (string, []string) {
+	p := strings.ReplaceAll(pattern, "{$}", "")
+	p = colonParam.ReplaceAllString(p, "{$1}")
+	p = wildcardParam.ReplaceAllString(p, "{$1}")
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	if p == "" {
+		p = "/"
+	}
+
+	var params []string
+	for _, m := range pathParam.FindAllStringSubmatch(p, -1) {
+		params = append(params, m[1])
+	}
+	return p, params
+}
+
+// addRoute describes the endpoint handler serves method and pattern with.
+// Routes whose handler is not a method of the project, such as inline
+// closures, are left out.
+func (b *openAPIBuilder) addRoute(src funcSource, call *ast.CallExpr, method, pattern string, handler ast.Expr) {
+	info := src.pkg.TypesInfo
+	fn, endpoint := b.endpoint(info, handler)
+	if fn == nil {
+		return
+	}
+
+	p, params := openAPIPath(pattern)
+	op := b.operation(fn, endpoint, params)
+	if b.secured(info, call, fn) || b.guarded && !b.isPublic(p) {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		if b.spec.Components.SecuritySchemes == nil {
+			b.spec.Components.SecuritySchemes = map[string]securityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			}
+		}
+	}
+
+	item, _ := b.spec.Paths.get(p)
+	item.set(strings.ToLower(method), op)
+	b.spec.Paths.set(p, item)
+}
+
+// endpoint finds the handler method in the handler expression of a route,
+// e.g. h.List in request.Echo(h.List).
+func (b *openAPIBuilder) endpoint(info *gotypes.Info, expr ast.Expr) (*gotypes.Func, funcSource) {
+	var found *gotypes.Func
+	ast.Inspect(expr, func(n ast.Node) bool {
+		var id *ast.Ident
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			id = n.Sel
+		case *ast.Ident:
+			id = n
+		default:
+			return found == nil
+		}
+		if fn, ok := info.Uses[id].(*gotypes.Func); ok && found == nil && isHandlerFunc(fn) {
+			if _, declared := b.funcs[fn]; declared {
+				found = fn
+			}
+		}
+		return found == nil
+	})
+	if found == nil {
+		return nil, funcSource{}
+	}
+	return found, b.funcs[found]
+}
+
+// isHandlerFunc reports whether fn is an http.HandlerFunc.
+func isHandlerFunc(fn *gotypes.Func) bool {
+	sig := fn.Type().(*gotypes.Signature)
+	if sig.Params().Len() != 2 || sig.Results().Len() != 0 {
+		return false
+	}
+	return sig.Params().At(0).Type().String() == "net/http.ResponseWriter" &&
+		sig.Params().At(1).Type().String() == "*net/http.Request"
+}
+
+// secured reports whether the route registered by call goes through an
+// authentication middleware, such as r.With(h.authenticate).Get(...).
+func (b *openAPIBuilder) secured(info *gotypes.Info, call *ast.CallExpr, endpoint *gotypes.Func) bool {
+	found := false
+	ast.Inspect(call, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		// Middlewares are functions, or fields holding one like h.authenticate
+		obj := info.Uses[id]
+		if obj == nil || obj == gotypes.Object(endpoint) || !isFunc(obj) {
+			return true
+		}
+		name := strings.ToLower(obj.Name())
+		if strings.Contains(name, "auth") || strings.Contains(name, "jwt") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isFunc reports whether obj is a function or a variable holding one.
+func isFunc(obj gotypes.Object) bool {
+	switch obj.(type) {
+	case *gotypes.Func, *gotypes.Var:
+		_, ok := obj.Type().Underlying().(*gotypes.Signature)
+		return ok
+	}
+	return false
+}
+
+// endpointFacts is what the body of an endpoint tells about it.
+type endpointFacts struct {
+	body      gotypes.Type
+	intParams map[string]bool
+	query     []*parameter
+	responses map[string]*response
+}
+
+// operation describes the endpoint fn declared by src, served at a path
+// with the params.
+func (b *openAPIBuilder) operation(fn *gotypes.Func, src funcSource, params []string) *operation {
+	tag := src.pkg.Name
+	if recv := fn.Type().(*gotypes.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if p, ok := t.(*gotypes.Pointer); ok {
+			t = p.Elem()
+		}
+		if named, ok := t.(*gotypes.Named); ok {
+			tag = strings.TrimSuffix(named.Obj().Name(), "Handler")
+		}
+	}
+	op := &operation{Tags: []string{tag}, Summary: docSummary(src.decl.Doc)}
+	op.OperationID = strings.ToLower(tag[:1]) + tag[1:] + fn.Name()
+	for i := 2; b.operations[op.OperationID]; i++ {
+		op.OperationID = strings.ToLower(tag[:1]) + tag[1:] + fn.Name() + strconv.Itoa(i)
+	}
+	b.operations[op.OperationID] = true
+
+	facts := &endpointFacts{intParams: make(map[string]bool), responses: make(map[string]*response)}
+	b.inspect(src, facts, 0)
+
+	for _, name := range params {
+		s := &schema{Type: "string"}
+		if facts.intParams[name] {
+			s = &schema{Type: "integer"}
+		}
+		op.Parameters = append(op.Parameters, &parameter{Name: name, In: "path", Required: true, Schema: s})
+	}
+	op.Parameters = append(op.Parameters, facts.query...)
+	if facts.body != nil {
+		op.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/json": {Schema: b.schemaFor(facts.body)}},
+		}
+	}
+
+	codes := make([]string, 0, len(facts.responses))
+	for code := range facts.responses {
+		codes = append(codes, code)
+	}
+	// Status codes in order, then default
+	slices.SortFunc(codes, func(a, b string) int {
+		return strings.Compare(strings.Replace(a, "default", "999", 1), strings.Replace(b, "default", "999", 1))
+	})
+	for _, code := range codes {
+		op.Responses.set(code, facts.responses[code])
+	}
+	if len(codes) == 0 {
+		op.Responses.set("default", &response{Description: "Response"})
+	}
+	return op
+}
+
+// docSummary is the first sentence of a doc comment.
+func docSummary(doc *ast.CommentGroup) string {
+	text := strings.Join(strings.Fields(doc.Text()), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return text
+}
+
+// inspect collects the facts of the body of src. Methods of the handler it
+// calls, such as h.fail, are inspected too.
+func (b *openAPIBuilder) inspect(src funcSource, facts *endpointFacts, depth int) {
+	info := src.pkg.TypesInfo
+	recv := receiverName(src.decl)
+	ast.Inspect(src.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := typeutil.StaticCallee(info, call)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+
+		switch fn.Pkg().Path() {
+		case b.layers.Request.Import:
+			b.requestCall(info, fn, call, facts)
+		case b.layers.Response.Import:
+			b.responseCall(info, fn, call, facts)
+		case b.layers.Pagination.Import:
+			if fn.Name() == "Parse" && len(call.Args) == 2 {
+				b.listParams(info, call.Args[1], facts)
+			}
+		case "net/url":
+			// r.URL.Query().Get("q")
+			if fn.FullName() == "(net/url.Values).Get" {
+				if name, ok := stringValue(info, call.Args[0]); ok && name != "" {
+					addQuery(facts, &parameter{Name: name, In: "query", Schema: &schema{Type: "string"}})
+				}
+			}
+		}
+
+		if helper, ok := b.funcs[fn]; ok && depth == 0 && recv != "" && receiverName(helper.decl) == recv {
+			b.inspect(helper, facts, depth+1)
+		}
+		return true
+	})
+}
+
+// receiverName is the type name of the receiver of a method, e.g.
+// OrderHandler, or "" for functions.
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	t := decl.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// requestCall notes the body and path parameters read with the request
+// package.
+func (b *openAPIBuilder) requestCall(info *gotypes.Info, fn *gotypes.Func, call *ast.CallExpr, facts *endpointFacts) {
+	switch fn.Name() {
+	case "ParseAndValidate", "ParseJSON":
+		if len(call.Args) == 2 {
+			if p, ok := info.TypeOf(call.Args[1]).(*gotypes.Pointer); ok {
+				facts.body = p.Elem()
+			}
+		}
+	case "GetURLParamInt":
+		if len(call.Args) == 2 {
+			if name, ok := stringValue(info, call.Args[1]); ok {
+				facts.intParams[name] = true
+			}
+		}
+	}
+}
+
+// responseCall notes the response a helper of the response package writes.
+func (b *openAPIBuilder) responseCall(info *gotypes.Info, fn *gotypes.Func, call *ast.CallExpr, facts *endpointFacts) {
+	message := func(status int) string {
+		if len(call.Args) > 1 {
+			if msg, ok := stringValue(info, call.Args[1]); ok && msg != "" {
+				return msg
+			}
+		}
+		return http.StatusText(status)
+	}
+	errorStatus := map[string]int{
+		"BadRequest":          http.StatusBadRequest,
+		"Unauthorized":        http.StatusUnauthorized,
+		"Forbidden":           http.StatusForbidden,
+		"NotFound":            http.StatusNotFound,
+		"InternalServerError": http.StatusInternalServerError,
+	}
+
+	switch name := fn.Name(); {
+	case name == "Success" || name == "Created":
+		status := http.StatusOK
+		if name == "Created" {
+			status = http.StatusCreated
+		}
+		var data *schema
+		if len(call.Args) == 3 && !isNil(info, call.Args[2]) {
+			data = b.schemaFor(info.TypeOf(call.Args[2]))
+		}
+		addResponse(facts, strconv.Itoa(status), message(status), b.envelope(data, nil))
+	case name == "Paginated" && len(call.Args) == 4:
+		items := b.schemaFor(info.TypeOf(call.Args[2]))
+		meta := b.schemaFor(info.TypeOf(call.Args[3]))
+		addResponse(facts, "200", message(http.StatusOK), b.envelope(items, meta))
+	case errorStatus[name] != 0:
+		status := errorStatus[name]
+		addResponse(facts, strconv.Itoa(status), message(status), b.errorContent())
+	case name == "JSONResponse" && len(call.Args) == 3:
+		tv := info.Types[call.Args[1]]
+		if tv.Value == nil {
+			return
+		}
+		status, _ := constant.Int64Val(tv.Value)
+		content := b.envelope(nil, nil)
+		if status >= http.StatusBadRequest {
+			content = b.errorContent()
+		}
+		addResponse(facts, strconv.Itoa(int(status)), http.StatusText(int(status)), content)
+	case name == "Error":
+		addResponse(facts, "default", "Error, with the status of its kind", b.errorContent())
+	}
+}
+
+func isNil(info *gotypes.Info, expr ast.Expr) bool {
+	return info.Types[expr].IsNil()
+}
+
+func addResponse(facts *endpointFacts, code, description string, content map[string]mediaType) {
+	if r, ok := facts.responses[code]; ok {
+		if !slices.Contains(strings.Split(r.Description, " or "), description) {
+			r.Description += " or " + description
+		}
+		return
+	}
+	facts.responses[code] = &response{Description: description, Content: content}
+}
+
+func addQuery(facts *endpointFacts, p *parameter) {
+	for _, q := range facts.query {
+		if q.Name == p.Name {
+			return
+		}
+	}
+	facts.query = append(facts.query, p)
+}
+
+// envelope is the content of a successful response: the APIResponse of the
+// response package carrying data, and meta for pages.
+func (b *openAPIBuilder) envelope(data, meta *schema) map[string]mediaType {
+	s := b.responseSchema("APIResponse")
+	if data != nil {
+		extra := &schema{Type: "object"}
+		extra.Properties.set("data", data)
+		if meta != nil {
+			extra.Properties.set("meta", meta)
+		}
+		s = &schema{AllOf: []*schema{s, extra}}
+	}
+	return map[string]mediaType{"application/json": {Schema: s}}
+}
+
+// errorContent is the content of error responses, in the format the
+// response package writes them: problem details, a JSON:API errors document
+// or the APIResponse envelope.
+func (b *openAPIBuilder) errorContent() map[string]mediaType {
+	switch {
+	case b.lookupResponse("NewProblem") != nil:
+		return map[string]mediaType{"application/problem+json": {Schema: b.responseSchema("Problem")}}
+	case b.lookupResponse("NewErrorDocument") != nil:
+		return map[string]mediaType{"application/vnd.api+json": {Schema: b.responseSchema("ErrorDocument")}}
+	}
+	return map[string]mediaType{"application/json": {Schema: b.responseSchema("APIResponse")}}
+}
+
+func (b *openAPIBuilder) lookupResponse(name string) gotypes.Object {
+	if b.response == nil {
+		return nil
+	}
+	return b.response.Scope().Lookup(name)
+}
+
+// responseSchema describes the type called name of the response package.
+func (b *openAPIBuilder) responseSchema(name string) *schema {
+	if obj, ok := b.lookupResponse(name).(*gotypes.TypeName); ok {
+		return b.schemaFor(obj.Type())
+	}
+	return &schema{Type: "object"}
+}
+
+// listParams adds the query parameters of pagination.Parse called with the
+// options expr, e.g. orderListOptions.
+func (b *openAPIBuilder) listParams(info *gotypes.Info, expr ast.Expr, facts *endpointFacts) {
+	var sorts, filters []string
+	if lit, litInfo := b.compositeLit(info, expr); lit != nil {
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, _ := kv.Key.(*ast.Ident)
+			values := stringList(litInfo, kv.Value)
+			switch {
+			case key == nil:
+			case key.Name == "Sort":
+				sorts = values
+			case key.Name == "Filter":
+				filters = values
+			}
+		}
+	}
+
+	one := 1.0
+	limit := &schema{Type: "integer", Minimum: &one}
+	if max, ok := b.paginationConst("MaxLimit"); ok {
+		limit.Maximum = &max
+	}
+	limitDescription := "Items per page"
+	if def, ok := b.paginationConst("DefaultLimit"); ok {
+		limitDescription += fmt.Sprintf(", %v by default", def)
+	}
+	addQuery(facts, &parameter{Name: "page", In: "query", Description: "Page number, from 1", Schema: &schema{Type: "integer", Minimum: &one}})
+	addQuery(facts, &parameter{Name: "limit", In: "query", Description: limitDescription, Schema: limit})
+	addQuery(facts, &parameter{Name: "cursor", In: "query", Description: "Switches to cursor pagination: empty for the first page, then the next_cursor of the meta", Schema: &schema{Type: "string"}})
+	if len(sorts) > 0 {
+		addQuery(facts, &parameter{
+			Name: "sort", In: "query",
+			Description: "Comma separated fields, prefixed with - to sort descending: " + strings.Join(sorts, ", "),
+			Schema:      &schema{Type: "string"},
+		})
+	}
+
+	operators := "ne, gt, gte, lt, lte, like, in"
+	if b.paginate != nil {
+		v := b.vars[b.paginate.Scope().Lookup("operators")]
+		if ops := stringList(v.info, v.value); len(ops) > 0 {
+			operators = strings.Join(slices.DeleteFunc(ops, func(op string) bool { return op == "eq" }), ", ")
+		}
+	}
+	for _, f := range filters {
+		addQuery(facts, &parameter{
+			Name: f, In: "query",
+			Description: fmt.Sprintf("Filter on %s, equal to the value. Use %s[op] for the operators %s", f, f, operators),
+			Schema:      &schema{Type: "string"},
+		})
+	}
+}
+
+// compositeLit is the composite literal of expr, or of the package-level
+// variable expr names, with the type information to evaluate it with.
+func (b *openAPIBuilder) compositeLit(info *gotypes.Info, expr ast.Expr) (*ast.CompositeLit, *gotypes.Info) {
+	var v varSource
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		return b.compositeLit(info, e.X)
+	case *ast.CompositeLit:
+		return e, info
+	case *ast.Ident:
+		v = b.vars[info.ObjectOf(e)]
+	case *ast.SelectorExpr:
+		v = b.vars[info.ObjectOf(e.Sel)]
+	}
+	lit, _ := v.value.(*ast.CompositeLit)
+	return lit, v.info
+}
+
+// stringList evaluates a []string{...} literal.
+func stringList(info *gotypes.Info, expr ast.Expr) []string {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, elt := range lit.Elts {
+		if tv, ok := info.Types[elt]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			values = append(values, constant.StringVal(tv.Value))
+		}
+	}
+	return values
+}
+
+// paginationConst is the value of a numeric constant of the pagination
+// package, e.g. MaxLimit.
+func (b *openAPIBuilder) paginationConst(name string) (float64, bool) {
+	if b.paginate == nil {
+		return 0, false
+	}
+	c, ok := b.paginate.Scope().Lookup(name).(*gotypes.Const)
+	if !ok {
+		return 0, false
+	}
+	v, _ := constant.Float64Val(c.Val())
+	return v, true
+}
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	gotypes "go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the OpenAPI 3.0 document gostart openapi writes.
+type openAPISpec struct {
+	OpenAPI    string                             `yaml:"openapi"`
+	Info       openAPIInfo                        `yaml:"info"`
+	Paths      orderedMap[orderedMap[*operation]] `yaml:"paths"`
+	Components openAPIComponents                  `yaml:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*schema        `yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]securityScheme `yaml:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type         string `yaml:"type"`
+	Scheme       string `yaml:"scheme"`
+	BearerFormat string `yaml:"bearerFormat,omitempty"`
+}
+
+type operation struct {
+	Tags        []string              `yaml:"tags,omitempty"`
+	Summary     string                `yaml:"summary,omitempty"`
+	OperationID string                `yaml:"operationId"`
+	Security    []map[string][]string `yaml:"security,omitempty"`
+	Parameters  []*parameter          `yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `yaml:"requestBody,omitempty"`
+	Responses   orderedMap[*response] `yaml:"responses"`
+}
+
+type parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]mediaType `yaml:"content"`
+}
+
+type response struct {
+	Description string               `yaml:"description"`
+	Content     map[string]mediaType `yaml:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+// schema is an OpenAPI 3.0 schema object.
+type schema struct {
+	Ref                  string              `yaml:"$ref,omitempty"`
+	AllOf                []*schema           `yaml:"allOf,omitempty"`
+	Type                 string              `yaml:"type,omitempty"`
+	Format               string              `yaml:"format,omitempty"`
+	Description          string              `yaml:"description,omitempty"`
+	Nullable             bool                `yaml:"nullable,omitempty"`
+	Enum                 []any               `yaml:"enum,omitempty"`
+	Minimum              *float64            `yaml:"minimum,omitempty"`
+	Maximum              *float64            `yaml:"maximum,omitempty"`
+	MinLength            *int                `yaml:"minLength,omitempty"`
+	MaxLength            *int                `yaml:"maxLength,omitempty"`
+	MinItems             *int                `yaml:"minItems,omitempty"`
+	MaxItems             *int                `yaml:"maxItems,omitempty"`
+	Pattern              string              `yaml:"pattern,omitempty"`
+	Items                *schema             `yaml:"items,omitempty"`
+	Properties           orderedMap[*schema] `yaml:"properties,omitempty"`
+	Required             []string            `yaml:"required,omitempty"`
+	AdditionalProperties *schema             `yaml:"additionalProperties,omitempty"`
+}
+
+// orderedMap is a YAML mapping written in insertion order, so properties
+// follow their struct and paths their routes.
+type orderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+func (m *orderedMap[V]) set(key string, value V) {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap[V]) get(key string) (V, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+func (m orderedMap[V]) IsZero() bool {
+	return len(m.keys) == 0
+}
+
+func (m orderedMap[V]) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		var value yaml.Node
+		if err := value.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &value)
+	}
+	return node, nil
+}
+
+// schemaRef points to the component schema called name.
+func schemaRef(name string) *schema {
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaFor describes how encoding/json writes values of t. Named structs
+// become component schemas, referred to by name.
+func (b *openAPIBuilder) schemaFor(t gotypes.Type) *schema {
+	t = gotypes.Unalias(t)
+	switch t := t.(type) {
+	case *gotypes.Pointer:
+		s := b.schemaFor(t.Elem())
+		if s.Ref != "" {
+			return &schema{AllOf: []*schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case *gotypes.Named:
+		return b.namedSchema(t)
+	case *gotypes.Basic:
+		return basicSchema(t)
+	case *gotypes.Slice:
+		if elem, ok := t.Elem().Underlying().(*gotypes.Basic); ok && elem.Kind() == gotypes.Byte {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case *gotypes.Array:
+		return &schema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case *gotypes.Map:
+		return &schema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem())}
+	case *gotypes.Struct:
+		return b.structSchema(t)
+	}
+	// Interfaces, funcs and channels hold anything
+	return &schema{}
+}
+
+// wellKnown are the schemas of library types that marshal themselves.
+var wellKnown = map[string]schema{
+	"time.Time":                   {Type: "string", Format: "date-time"},
+	"time.Duration":               {Type: "integer", Format: "int64"},
+	"encoding/json.RawMessage":    {},
+	"encoding/json.Number":        {Type: "number"},
+	"database/sql.NullString":     {Type: "string", Nullable: true},
+	"database/sql.NullInt64":      {Type: "integer", Format: "int64", Nullable: true},
+	"database/sql.NullInt32":      {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullFloat64":    {Type: "number", Format: "double", Nullable: true},
+	"database/sql.NullBool":       {Type: "boolean", Nullable: true},
+	"database/sql.NullTime":       {Type: "string", Format: "date-time", Nullable: true},
+	"gorm.io/gorm.DeletedAt":      {Type: "string", Format: "date-time", Nullable: true},
+	"gorm.io/datatypes.JSON":      {},
+	"gorm.io/datatypes.Date":      {Type: "string", Format: "date"},
+	"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"},
+}
+
+func (b *openAPIBuilder) namedSchema(t *gotypes.Named) *schema {
+	obj := t.Obj()
+	if obj.Pkg() != nil {
+		if s, ok := wellKnown[obj.Pkg().Path()+"."+obj.Name()]; ok {
+			return &s
+		}
+	}
+	switch {
+	case hasMethod(t, "MarshalJSON"):
+		return &schema{}
+	case hasMethod(t, "MarshalText"):
+		return &schema{Type: "string"}
+	}
+
+	st, ok := t.Underlying().(*gotypes.Struct)
+	if !ok || obj.Pkg() == nil || t.TypeArgs().Len() > 0 {
+		return b.schemaFor(t.Underlying())
+	}
+	if name, ok := b.schemaNames[obj]; ok {
+		return schemaRef(name)
+	}
+
+	// Named before its fields are described, so recursive types end
+	name := exportedName(obj.Name())
+	if other, taken := b.schemaTypes[name]; taken && other != obj {
+		name = exportedName(obj.Pkg().Name()) + name
+	}
+	b.schemaNames[obj] = name
+	b.schemaTypes[name] = obj
+	b.spec.Components.Schemas[name] = b.structSchema(st)
+	return schemaRef(name)
+}
+
+func hasMethod(t gotypes.Type, name string) bool {
+	set := gotypes.NewMethodSet(gotypes.NewPointer(t))
+	for i := range set.Len() {
+		if set.At(i).Obj().Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func basicSchema(t *gotypes.Basic) *schema {
+	switch t.Kind() {
+	case gotypes.Bool:
+		return &schema{Type: "boolean"}
+	case gotypes.Int, gotypes.Int8, gotypes.Int16, gotypes.Int32:
+		return &schema{Type: "integer", Format: "int32"}
+	case gotypes.Int64:
+		return &schema{Type: "integer", Format: "int64"}
+	case gotypes.Uint, gotypes.Uint8, gotypes.Uint16, gotypes.Uint32, gotypes.Uint64, gotypes.Uintptr:
+		zero := 0.0
+		return &schema{Type: "integer", Minimum: &zero}
+	case gotypes.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case gotypes.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case gotypes.String:
+		return &schema{Type: "string"}
+	}
+	return &schema{}
+}
+
+// structSchema describes the JSON object of st, flattening embedded structs
+// the way encoding/json does.
+func (b *openAPIBuilder) structSchema(st *gotypes.Struct) *schema {
+	s := &schema{Type: "object"}
+	b.addFields(s, st)
+	return s
+}
+
+func (b *openAPIBuilder) addFields(s *schema, st *gotypes.Struct) {
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if f.Embedded() && name == "" {
+			ft := gotypes.Unalias(f.Type())
+			if p, ok := ft.(*gotypes.Pointer); ok {
+				ft = gotypes.Unalias(p.Elem())
+			}
+			if embedded, ok := ft.Underlying().(*gotypes.Struct); ok {
+				if _, marshals := wellKnown[typeName(ft)]; !marshals && !hasMethod(ft, "MarshalJSON") {
+					b.addFields(s, embedded)
+					continue
+				}
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name()
+		}
+
+		prop := b.schemaFor(f.Type())
+		if strings.Contains(","+opts+",", ",string,") {
+			prop = &schema{Type: "string", Nullable: prop.Nullable}
+		}
+		if applyValidateTag(prop, f.Type(), tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties.set(name, prop)
+	}
+}
+
+// applyValidateTag adds the constraints of the validate tag of a field of
+// type t to s, reporting whether the field is required.
+func applyValidateTag(s *schema, t gotypes.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	if p, ok := gotypes.Unalias(t).(*gotypes.Pointer); ok {
+		t = p.Elem()
+	}
+	target := s
+	if len(s.AllOf) > 0 {
+		// Constraints do not apply to a referenced object
+		target = &schema{}
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			required = true
+		case "notblank":
+			target.Pattern = `\S`
+		case "email":
+			target.Format = "email"
+		case "uuid":
+			target.Format = "uuid"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				target.Enum = append(target.Enum, enumValue(target.Type, v))
+			}
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			setLimit(target, t, limit, name == "min")
+		}
+	}
+	return required
+}
+
+func enumValue(typ, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// setLimit applies a min or max rule: a length for strings, a number of
+// items for slices and maps, a value for numbers.
+func setLimit(s *schema, t gotypes.Type, limit float64, least bool) {
+	n := int(limit)
+	switch u := t.Underlying().(type) {
+	case *gotypes.Basic:
+		if u.Info()&gotypes.IsString != 0 {
+			if least {
+				s.MinLength = &n
+			} else {
+				s.MaxLength = &n
+			}
+			return
+		}
+		if least {
+			s.Minimum = &limit
+		} else {
+			s.Maximum = &limit
+		}
+	case *gotypes.Slice, *gotypes.Array, *gotypes.Map:
+		if least {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	}
+}
+
+// typeName is the qualified name of a named type, e.g. time.Time.
+func typeName(t gotypes.Type) string {
+	if n, ok := gotypes.Unalias(t).(*gotypes.Named); ok && n.Obj().Pkg() != nil {
+		return n.Obj().Pkg().Path() + "." + n.Obj().Name()
+	}
+	return ""
+}
+
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestMain runs gostart itself when GOSTART_ARGS is set, so that the tests
+// can run commands in a fresh process, including ones failing with fatalf.
+func TestMain(m *testing.M) {
+	if args := os.Getenv("GOSTART_ARGS"); args != "" {
+		root := &cobra.Command{
+			Use:               "gostart",
+			PersistentPreRun:  func(c *cobra.Command, args []string) { ApplyConfigDefaults(c) },
+			PersistentPostRun: func(c *cobra.Command, args []string) { Finish(os.Stdout) },
+		}
+		root.PersistentFlags().BoolVar(&DryRun, "dry-run", false, "")
+		root.PersistentFlags().BoolVar(&Force, "force", false, "")
+		root.PersistentFlags().BoolVar(&SkipExisting, "skip-existing", false, "")
+		root.PersistentFlags().BoolVarP(&Interactive, "interactive", "i", false, "")
+		root.AddCommand(InitCmd, CreateCmd, DestroyCmd)
+		root.SetArgs(strings.Fields(args))
+		if err := root.Execute(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// gostart runs a gostart command in dir and returns its output.
+func gostart(t *testing.T, dir, args string) (string, error) {
+	t.Helper()
+	c := exec.Command(os.Args[0], "-test.run=^$")
+	c.Dir = dir
+	c.Env = append(os.Environ(), "GOSTART_ARGS="+args)
+	out, err := c.CombinedOutput()
+	return string(out), err
+}
+
+func mustGostart(t *testing.T, dir, args string) string {
+	t.Helper()
+	out, err := gostart(t, dir, args)
+	if err != nil || strings.Contains(out, "❌") {
+		t.Fatalf("gostart %s: %v\n%s", args, err, out)
+	}
+	return out
+}
+
+func newProject(t *testing.T, initArgs string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustGostart(t, dir, "init "+initArgs)
+	return dir
+}
+
+// snapshot reads every file of dir.
+func snapshot(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		files[p] = string(content)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func assertUnchanged(t *testing.T, before, after map[string]string) {
+	t.Helper()
+	for p, content := range before {
+		if got, ok := after[p]; !ok {
+			t.Errorf("%s was removed", p)
+		} else if got != content {
+			t.Errorf("%s was changed", p)
+		}
+	}
+	for p := range after {
+		if _, ok := before[p]; !ok {
+			t.Errorf("%s was created", p)
+		}
+	}
+}
+
+func readProjectFile(t *testing.T, dir, p string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+const (
+	bootstrapFile = "internal/app/bootstrap/bootstrap.go"
+	registryFile  = "internal/infrastructure/databases/models/registry.go"
+	handlersDir   = "internal/interface/handlers"
+)
+
+func TestModelRegistry(t *testing.T) {
+	for _, db := range []string{"sqlite", "postgres", "mysql", "sqlserver"} {
+		t.Run(db, func(t *testing.T) {
+			dir := newProject(t, "--router chi --db "+db)
+
+			mustGostart(t, dir, "create feature order --fields total:int")
+			if registry := readProjectFile(t, dir, registryFile); !strings.Contains(registry, "&Order{}") {
+				t.Errorf("create feature left Order out of the registry:\n%s", registry)
+			}
+
+			mustGostart(t, dir, "destroy feature order")
+			if registry := readProjectFile(t, dir, registryFile); strings.Contains(registry, "&Order{}") {
+				t.Errorf("destroy feature left Order in the registry:\n%s", registry)
+			}
+		})
+	}
+}
+
+func TestRefusedCommandsChangeNothing(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   []string
+		edit    func(t *testing.T, dir string)
+		command string
+		want    string
+	}{
+		{
+			name:    "init after features",
+			setup:   []string{"create feature order", "create feature admin/user_role"},
+			command: "init --router chi --db sqlite",
+			want:    "holds code later gostart commands added",
+		},
+		{
+			name:  "init with edited files",
+			setup: []string{"create feature order"},
+			edit: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, "cmd/main.go"), "// mine\n")
+			},
+			command: "init --router chi --db sqlite",
+			want:    "cmd/main.go was edited",
+		},
+		{
+			name:  "destroy with an edited handler",
+			setup: []string{"create feature order --fields total:int"},
+			edit: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, handlersDir, "order_handler.go"), "// mine\n")
+			},
+			command: "destroy feature order",
+			want:    "nothing was removed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newProject(t, "--router chi --db sqlite")
+			for _, args := range tt.setup {
+				mustGostart(t, dir, args)
+			}
+			if tt.edit != nil {
+				tt.edit(t, dir)
+			}
+
+			before := snapshot(t, dir)
+			out, err := gostart(t, dir, tt.command)
+			if err == nil || !strings.Contains(out, tt.want) {
+				t.Fatalf("gostart %s: %v, want a failure mentioning %q\n%s", tt.command, err, tt.want, out)
+			}
+			assertUnchanged(t, before, snapshot(t, dir))
+		})
+	}
+}
+
+func TestReinitKeepsWiring(t *testing.T) {
+	dir := newProject(t, "--router chi --db sqlite")
+	mustGostart(t, dir, "create feature order")
+	mustGostart(t, dir, "create feature admin/user_role")
+
+	mustGostart(t, dir, "init --router chi --db sqlite --skip-existing")
+	bootstrap := readProjectFile(t, dir, bootstrapFile)
+	for _, want := range []string{"handler.NewOrderHandler(", "admin.NewUserRoleHandler("} {
+		if !strings.Contains(bootstrap, want) {
+			t.Errorf("init --skip-existing dropped %s from bootstrap.go:\n%s", want, bootstrap)
+		}
+	}
+}
+
+func TestFailedCommandRecordsWrittenFiles(t *testing.T) {
+	dir := newProject(t, "--router chi --db sqlite")
+	appendFile(t, filepath.Join(dir, handlersDir, "widget_handler.go"), "package handler\n")
+
+	if out, err := gostart(t, dir, "create feature widget"); err == nil {
+		t.Fatalf("create feature widget overwrote a hand-written handler:\n%s", out)
+	}
+	manifest := readProjectFile(t, dir, manifestPath)
+	for _, want := range []string{`"kind": "usecase"`, `"kind": "repository"`} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest lacks %s of the files written before the failure:\n%s", want, manifest)
+		}
+	}
+
+	appendFile(t, filepath.Join(dir, handlersDir, "gadget_handler.go"), "package handler\n")
+	out, err := gostart(t, dir, "--dry-run create feature gadget --fields n:int")
+	if err == nil || !strings.Contains(out, "+++ b/") {
+		t.Errorf("failed dry run printed no diff: %v\n%s", err, out)
+	}
+}
+
+func appendFile(t *testing.T, p, text string) {
+	t.Helper()
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
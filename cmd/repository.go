@@ -1,150 +1,207 @@
 package cmd
 
 import (
-	"bytes"
-	_ "embed"
 	"fmt"
 	"go/format"
 	"log"
-	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-//go:embed templates/repository.tmpl
-var repositoryTemplate string
-
-//go:embed templates/repository_interface.tmpl
-var repositoryInterfaceTemplate string
-
 var RepositoryCmd = &cobra.Command{
 	Use:   "repository [name]",
 	Short: "Create a new repository",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := strings.ToLower(args[0])
-		caser := cases.Title(language.English)
-		serviceName := caser.String(name)
+		n := mustParseComponentName(args[0])
 
-		destDir := fmt.Sprintf("internal/infrastructure/repositories/%s", name)
-		if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
-			log.Fatalf("❌ Failed to create repositories directory: %v", err)
+		cfg := projectConfig()
+		destDir := filepath.Join(cfg.Layers.Repositories.Dir, filepath.FromSlash(n.Dir))
+		if err := makeDir(destDir); err != nil {
+			fatalf("❌ Failed to create repositories directory: %v", err)
 		}
 
-		templateData := types.TemplateData{
-			ServiceName:      serviceName,
-			ServiceNameLower: name,
+		moduleName, _ := getModuleName()
+
+		templateData := newComponentData(moduleName, n)
+		templateData.Fields = templateFields()
+
+		// Field-based generation swaps the stubs for CRUD templates
+		repositoryTmpl, interfaceTmplName := "repository.tmpl", "repository_interface.tmpl"
+		if len(templateData.Fields) > 0 {
+			repositoryTmpl, interfaceTmplName = "repository_crud.tmpl", "repository_interface_crud.tmpl"
+			ensurePagination(templateData)
+			ensureAppErrors(templateData)
 		}
 
-		// Parse and write repository.tmpl
-		tmpl, err := template.New("repository").Parse(repositoryTemplate)
+		// Render repository.tmpl
+		content, err := executeTemplate(repositoryTmpl, templateData)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse embedded repository template: %v", err)
+			fatalf("❌ %v", err)
 		}
-		var buf bytes.Buffer
-		err = tmpl.Execute(&buf, templateData)
+		outputPath := filepath.Join(destDir, fileName(cfg.Files.Repository, n.Snake))
+		written, err := writeGenerated(outputPath, content)
 		if err != nil {
-			log.Fatalf("❌ Failed to execute repository template: %v", err)
+			fatalf("❌ Failed to write repository file: %v", err)
 		}
-		outputPath := filepath.Join(destDir, fmt.Sprintf("%s_repository.go", name))
-		err = os.WriteFile(outputPath, buf.Bytes(), 0644)
-		if err != nil {
-			log.Fatalf("❌ Failed to write repository file: %v", err)
+		if written {
+			recordGenerated("repository", componentName(n), outputPath, repositoryTmpl, content)
+			fmt.Println("✅ Repository created at:", outputPath)
 		}
-		fmt.Println("✅ Repository created at:", outputPath)
 
-		// Parse and write repository_interface.tmpl
-		interfaceTmpl, err := template.New("repository_interface").Parse(repositoryInterfaceTemplate)
+		// Render repository_interface.tmpl
+		interfaceContent, err := executeTemplate(interfaceTmplName, templateData)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse embedded interface template: %v", err)
+			fatalf("❌ %v", err)
 		}
-		var interfaceBuf bytes.Buffer
-		err = interfaceTmpl.Execute(&interfaceBuf, templateData)
+		interfacePath := filepath.Join(destDir, fileName(cfg.Files.Interface, n.Snake))
+		written, err = writeGenerated(interfacePath, interfaceContent)
 		if err != nil {
-			log.Fatalf("❌ Failed to execute interface template: %v", err)
+			fatalf("❌ Failed to write interface.go: %v", err)
 		}
-		interfacePath := filepath.Join(destDir, "interface.go")
-		err = os.WriteFile(interfacePath, interfaceBuf.Bytes(), 0644)
-		if err != nil {
-			log.Fatalf("❌ Failed to write interface.go: %v", err)
+		if written {
+			recordGenerated("repository", componentName(n), interfacePath, interfaceTmplName, interfaceContent)
+			fmt.Println("✅ Interface created at:", interfacePath)
+		}
+
+		// Tests run against an in-memory SQLite database
+		testTmpl := "repository_test.tmpl"
+		if len(templateData.Fields) > 0 {
+			testTmpl = "repository_crud_test.tmpl"
+		}
+		if err := requireModules("gorm.io/driver/sqlite"); err != nil {
+			fatalf("❌ Failed to update go.mod: %v", err)
+		}
+		if err := generateTest("repository", n, filepath.Join(destDir, testFileName(cfg.Files.Repository, n.Snake)), testTmpl, templateData); err != nil {
+			fatalf("❌ %v", err)
+		}
+		if err := generateMock("repository", n, interfacePath, n.Pascal+"Repository", templateData.Layers.Repositories.Import+"/"+n.Dir, n.Package+"repo", fileName(cfg.Files.Repository, n.Snake)); err != nil {
+			fatalf("❌ Failed to write the repository mock: %v", err)
 		}
-		fmt.Println("✅ Interface created at:", interfacePath)
 
 		// Update repositories.go
-		err = createOrUpdateRepositoriesIndex(serviceName, name)
+		err = createOrUpdateRepositoriesIndex(n.Pascal, n.Dir)
 		if err != nil {
-			log.Fatalf("❌ Failed to create/update repositories.go: %v", err)
+			fatalf("❌ Failed to create/update repositories.go: %v", err)
 		}
-		fmt.Println("✅ Repositories index updated at: internal/infrastructure/repositories/repositories.go")
+		fmt.Println("✅ Repositories index updated at:", repositoryIndexPath())
 	},
 }
 
+// ensurePagination generates the pagination package, used by the CRUD
+// repositories and handlers, in projects created before it.
+func ensurePagination(data types.TemplateData) {
+	files := []struct{ path, tmpl string }{
+		{filepath.Join(data.Layers.Pagination.Dir, "pagination.go"), "pagination.tmpl"},
+		{filepath.Join(data.Layers.Response.Dir, "paginated.go"), "response_paginated.tmpl"},
+	}
+	for _, f := range files {
+		if fileExists(f.path) {
+			continue
+		}
+		written, err := generateFile("pagination", "pagination", f.path, f.tmpl, data)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		if written {
+			fmt.Println("✅ Created:", f.path)
+		}
+	}
+}
+
+// ensureAppErrors generates the apperror package and the response helper
+// mapping its kinds to statuses in projects created before them.
+func ensureAppErrors(data types.TemplateData) {
+	files := []struct{ path, tmpl string }{
+		{filepath.Join(data.Layers.Errors.Dir, "apperror.go"), "apperror.tmpl"},
+		{filepath.Join(data.Layers.Response.Dir, "error.go"), "response_error.tmpl"},
+	}
+	for _, f := range files {
+		if fileExists(f.path) {
+			continue
+		}
+		written, err := generateFile("apperror", "apperror", f.path, f.tmpl, data)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		if written {
+			fmt.Println("✅ Created:", f.path)
+		}
+	}
+}
+
 func createOrUpdateRepositoriesIndex(serviceName, name string) error {
 	moduleName, err := getModuleName()
 	if err != nil {
 		return fmt.Errorf("failed to get module name: %w", err)
 	}
 
-	repositoriesPath := "internal/infrastructure/repositories/repositories.go"
+	repositoriesPath := repositoryIndexPath()
 
-	if _, err := os.Stat(repositoriesPath); os.IsNotExist(err) {
+	if !fileExists(repositoriesPath) {
 		return createNewRepositoriesIndex(repositoriesPath, moduleName, serviceName, name)
 	}
 	return updateExistingRepositoriesIndex(repositoriesPath, moduleName, serviceName, name)
 }
 
-func createNewRepositoriesIndex(path, moduleName, serviceName, name string) error {
-	content := fmt.Sprintf(`package repositories
-
-import "%s/internal/infrastructure/repositories/%s"
-
-type %sRepository = %s.%sRepository
+// repositoryIndexPath is the file aliasing every repository, e.g.
+// internal/infrastructure/repositories/repositories.go.
+func repositoryIndexPath() string {
+	layer := projectConfig().Layers.Repositories
+	return filepath.Join(layer.Dir, layer.Package+".go")
+}
 
-var (
-	New%sRepository = %s.New%sRepository
-)
-`, moduleName, name, serviceName, name, serviceName, serviceName, name, serviceName)
+func createNewRepositoriesIndex(path, moduleName, serviceName, name string) error {
+	content := generateCleanRepositoriesFile([]RepositoryEntry{{
+		Name:        name,
+		ServiceName: serviceName,
+		ModuleName:  moduleName,
+	}})
 
 	formattedContent, err := format.Source([]byte(content))
 	if err != nil {
 		formattedContent = []byte(content)
 	}
-	return os.WriteFile(path, formattedContent, 0644)
+	return writeFile(path, formattedContent)
 }
 
 // RepositoryEntry represents a single repository entry
 type RepositoryEntry struct {
-	Name        string // e.g., "user"
+	Name        string // e.g., "user" or "shop/orderitem"
 	ServiceName string // e.g., "User"
 	ModuleName  string
 }
 
 func updateExistingRepositoriesIndex(path, moduleName, serviceName, name string) error {
-	content, err := os.ReadFile(path)
+	content, err := readFile(path)
 	if err != nil {
 		return err
 	}
 	contentStr := string(content)
 
-	// Skip if already exists
-	if strings.Contains(contentStr, fmt.Sprintf("New%sRepository", serviceName)) {
-		return nil
-	}
-
 	// Parse existing entries
 	entries, err := parseExistingRepositoryEntries(contentStr, moduleName)
 	if err != nil {
 		return err
 	}
 
+	// Skip if already exists, the aliases are flat so names must be unique
+	for _, entry := range entries {
+		if entry.ServiceName != serviceName {
+			continue
+		}
+		if entry.Name != name {
+			return fmt.Errorf("%sRepository is already declared by %s, choose another name", serviceName, entry.Name)
+		}
+		return nil
+	}
+
 	// Add new entry
 	entries = append(entries, RepositoryEntry{
 		Name:        name,
@@ -167,46 +224,82 @@ func updateExistingRepositoriesIndex(path, moduleName, serviceName, name string)
 		formatted = []byte(newContent)
 	}
 
-	return os.WriteFile(path, formatted, 0644)
+	return writeFile(path, formatted)
 }
 
-func parseExistingRepositoryEntries(content, moduleName string) ([]RepositoryEntry, error) {
-	var entries []RepositoryEntry
+// removeFromRepositoriesIndex drops the repository in package directory name from the
+// repositories index, deleting the index once nothing is left in it.
+func removeFromRepositoriesIndex(name string) error {
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
 
-	// Extract imports
-	importRegex := regexp.MustCompile(`"` + regexp.QuoteMeta(moduleName) + `/internal/infrastructure/repositories/([^"]+)"`)
-	importMatches := importRegex.FindAllStringSubmatch(content, -1)
+	indexPath := repositoryIndexPath()
+	if !fileExists(indexPath) {
+		return nil
+	}
+	content, err := readFile(indexPath)
+	if err != nil {
+		return err
+	}
 
-	// Extract type aliases
-	typeRegex := regexp.MustCompile(`(\w+)Repository\s*=\s*(\w+)\.(\w+)Repository`)
-	typeMatches := typeRegex.FindAllStringSubmatch(content, -1)
+	entries, err := parseExistingRepositoryEntries(string(content), moduleName)
+	if err != nil {
+		return err
+	}
+	var kept []RepositoryEntry
+	for _, entry := range entries {
+		if entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return removeFile(indexPath)
+	}
 
-	// Create a map for easier lookup
-	typeMap := make(map[string]string) // serviceName -> name
-	for _, match := range typeMatches {
-		if len(match) >= 4 {
-			serviceName := match[1] // e.g., "User"
-			packageName := match[2] // e.g., "user"
-			typeMap[serviceName] = packageName
+	newContent := generateCleanRepositoriesFile(kept)
+	formatted, err := format.Source([]byte(newContent))
+	if err != nil {
+		formatted = []byte(newContent)
+	}
+	return writeFile(indexPath, formatted)
+}
+
+func parseExistingRepositoryEntries(content, moduleName string) ([]RepositoryEntry, error) {
+	var entries []RepositoryEntry
+
+	// Extract imports, keyed by the identifier the file uses for them
+	layer := projectConfig().Layers.Repositories
+	importRegex := regexp.MustCompile(`(?m)^\s*(?:(\w+)\s+)?"` + regexp.QuoteMeta(moduleName+"/"+layer.Dir) + `/([^"]+)"`)
+	packages := make(map[string]string) // identifier -> name, e.g. "orderitem" -> "shop/orderitem"
+	for _, match := range importRegex.FindAllStringSubmatch(content, -1) {
+		ident := match[1]
+		if ident == "" || ident == "import" {
+			ident = path.Base(match[2])
 		}
+		packages[ident] = match[2]
 	}
 
-	// Build entries based on imports
-	for _, match := range importMatches {
-		if len(match) >= 2 {
-			packageName := match[1] // e.g., "user"
-			// Convert to service name (capitalize first letter)
-			caser := cases.Title(language.English)
-			serviceName := caser.String(packageName)
+	// Extract type aliases, only from type declarations since the var block
+	// repeats the pattern for the constructors
+	typeDecls := typeDeclarations(content)
+	typeRegex := regexp.MustCompile(`(\w+)Repository\s*=\s*(\w+)\.(\w+)Repository`)
+	typeMatches := typeRegex.FindAllStringSubmatch(typeDecls, -1)
 
-			// Verify this entry exists in types
-			if _, exists := typeMap[serviceName]; exists {
-				entries = append(entries, RepositoryEntry{
-					Name:        packageName,
-					ServiceName: serviceName,
-					ModuleName:  moduleName,
-				})
-			}
+	// Build entries based on type aliases pointing at an imported package
+	for _, match := range typeMatches {
+		serviceName := match[1] // e.g., "OrderItem"
+		packageName := match[2] // e.g., "orderitem"
+		if name, exists := packages[packageName]; exists && match[3] == serviceName {
+			entries = append(entries, RepositoryEntry{
+				Name:        name,
+				ServiceName: serviceName,
+				ModuleName:  moduleName,
+			})
 		}
 	}
 
@@ -216,13 +309,14 @@ func parseExistingRepositoryEntries(content, moduleName string) ([]RepositoryEnt
 func generateCleanRepositoriesFile(entries []RepositoryEntry) string {
 	var buf strings.Builder
 
-	buf.WriteString("package repositories\n\n")
+	layer := projectConfig().Layers.Repositories
+	buf.WriteString("package " + layer.Package + "\n\n")
 
 	// Generate imports
 	if len(entries) > 0 {
 		buf.WriteString("import (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\t\"%s/internal/infrastructure/repositories/%s\"\n", entry.ModuleName, entry.Name))
+			buf.WriteString(fmt.Sprintf("\t\"%s/%s/%s\"\n", entry.ModuleName, layer.Dir, entry.Name))
 		}
 		buf.WriteString(")\n\n")
 	}
@@ -231,7 +325,7 @@ func generateCleanRepositoriesFile(entries []RepositoryEntry) string {
 	if len(entries) > 0 {
 		buf.WriteString("type (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\t%sRepository = %s.%sRepository\n", entry.ServiceName, entry.Name, entry.ServiceName))
+			buf.WriteString(fmt.Sprintf("\t%sRepository = %s.%sRepository\n", entry.ServiceName, path.Base(entry.Name), entry.ServiceName))
 		}
 		buf.WriteString(")\n\n")
 	}
@@ -240,7 +334,7 @@ func generateCleanRepositoriesFile(entries []RepositoryEntry) string {
 	if len(entries) > 0 {
 		buf.WriteString("var (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\tNew%sRepository = %s.New%sRepository\n", entry.ServiceName, entry.Name, entry.ServiceName))
+			buf.WriteString(fmt.Sprintf("\tNew%sRepository = %s.New%sRepository\n", entry.ServiceName, path.Base(entry.Name), entry.ServiceName))
 		}
 		buf.WriteString(")\n")
 	}
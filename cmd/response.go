@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// errorFormats lists the bodies error responses can be written as: the
+// success/message envelope, RFC 7807 problem details or a JSON:API errors
+// document.
+var errorFormats = []string{"envelope", "jsonapi", "problem"}
+
+// lookupErrorFormat checks that name is a known error format.
+func lookupErrorFormat(name string) error {
+	if !slices.Contains(errorFormats, name) {
+		return fmt.Errorf("unknown error format %q (use %s)", name, strings.Join(errorFormats, ", "))
+	}
+	return nil
+}
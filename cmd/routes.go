@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+)
+
+// routerFramework describes how the generated code uses a router.
+type routerFramework struct {
+	module       string   // module the generated code requires, empty for the standard library
+	constructors []string // calls InitRouter may create its router with, e.g. chi.NewRouter
+}
+
+// routers lists the routers init can generate a project for.
+var routers = map[string]routerFramework{
+	"chi":    {"github.com/go-chi/chi/v5", []string{"chi.NewRouter"}},
+	"stdlib": {"", []string{"http.NewServeMux"}},
+	"echo":   {"github.com/labstack/echo/v4", []string{"echo.New"}},
+	"gin":    {"github.com/gin-gonic/gin", []string{"gin.New", "gin.Default"}},
+	"fiber":  {"github.com/gofiber/fiber/v2", []string{"fiber.New"}},
+}
+
+// lookupRouter returns the router called name.
+func lookupRouter(name string) (routerFramework, error) {
+	r, ok := routers[name]
+	if !ok {
+		return r, fmt.Errorf("unknown router %q (use %s)", name, strings.Join(routerNames(), ", "))
+	}
+	return r, nil
+}
+
+func routerNames() []string {
+	names := make([]string, 0, len(routers))
+	for name := range routers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// routerFilePath is the file holding InitRouter, e.g. internal/interface/routes/router.go.
+func routerFilePath() string {
+	return filepath.Join(projectConfig().Layers.Routes.Dir, "router.go")
+}
+
+// routeGroup is the path every feature is mounted under, built from the
+// configured prefix and API version, e.g. /api/v1. It is "" when both are empty.
+func routeGroup() string {
+	cfg := projectConfig().Routes
+	group := path.Join("/", cfg.Prefix, cfg.Version)
+	if group == "/" {
+		return ""
+	}
+	return group
+}
+
+// routeMountPath is the path a feature is mounted at inside its group, e.g.
+// /shop/order-items for shop/order_item.
+func routeMountPath(n types.Name) string {
+	last := n.Kebab
+	if p := projectConfig().Routes.Pluralize; p == nil || *p {
+		last = n.PluralKebab
+	}
+	return "/" + path.Join(append(append([]string{}, n.Parents...), last)...)
+}
+
+// injectRoute mounts the routes of a handler in InitRouter. With chi they go
+// inside a r.Route(group, ...) block when group is set:
+//
+//	r.Route("/api/v1", func(r chi.Router) {
+//		r.Mount("/orders", deps.OrderHandler.Routes())
+//	})
+//
+// The other routers get the full path: deps.OrderHandler.Routes(mux,
+// "/api/v1/orders") with stdlib, or deps.OrderHandler.Routes(e.Group(
+// "/api/v1/orders")) with echo, gin and fiber.
+func injectRoute(pascal, group, mountPath string) error {
+	framework := projectConfig().Router
+	r, err := lookupRouter(framework)
+	if err != nil {
+		return err
+	}
+
+	routerPath := routerFilePath()
+	src, err := readFile(routerPath)
+	if err != nil {
+		return err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return err
+	}
+
+	fn := s.findFunc("InitRouter")
+	if fn == nil {
+		return fmt.Errorf("%s: expected a `func InitRouter(deps *bootstrap.Dependencies) http.Handler` function", routerPath)
+	}
+	if len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return fmt.Errorf("%s: InitRouter must take the bootstrap dependencies as its first parameter", routerPath)
+	}
+	deps := fn.Type.Params.List[0].Names[0].Name
+
+	router := routerVar(fn, r.constructors)
+	if router == "" {
+		return fmt.Errorf("%s: InitRouter must create its router with `%s()`", routerPath, r.constructors[0])
+	}
+
+	ret, ok := fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
+	if !ok {
+		return fmt.Errorf("%s: InitRouter must end with `return %s`", routerPath, router)
+	}
+
+	field := pascal + "Handler"
+	mounted := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == field {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == deps {
+				mounted = true
+			}
+		}
+		return !mounted
+	})
+	if mounted {
+		return nil
+	}
+
+	mount := func(router string) string {
+		return fmt.Sprintf("%s.Mount(%s, %s.%s.Routes())", router, strconv.Quote(mountPath), deps, field)
+	}
+
+	fullPath := path.Join("/", group, mountPath)
+	switch lit := routeGroupFunc(fn, router, group); {
+	case framework == "stdlib":
+		s.insert(ret.Pos(), fmt.Sprintf("%s.%s.Routes(%s, %s)\n\n\t", deps, field, router, strconv.Quote(fullPath)))
+	case framework != "chi":
+		s.insert(ret.Pos(), fmt.Sprintf("%s.%s.Routes(%s.Group(%s))\n\n\t", deps, field, router, strconv.Quote(fullPath)))
+	case group == "":
+		s.insert(ret.Pos(), mount(router)+"\n\n\t")
+	case lit != nil:
+		inner := router
+		if params := lit.Type.Params.List; len(params) == 1 && len(params[0].Names) == 1 {
+			inner = params[0].Names[0].Name
+		}
+		var last ast.Node
+		if n := len(lit.Body.List); n > 0 {
+			last = lit.Body.List[n-1]
+		}
+		s.insertLine(lit.Body.Rbrace, last, mount(inner), false)
+	default:
+		chi := s.ensureImport("github.com/go-chi/chi/v5", "chi")
+		block := fmt.Sprintf("%s.Route(%s, func(%s %s.Router) {\n\t\t%s\n\t})", router, strconv.Quote(group), router, chi, mount(router))
+		s.insert(ret.Pos(), block+"\n\n\t")
+	}
+
+	out, err := s.bytes()
+	if err != nil {
+		return err
+	}
+	return writeTracked(routerPath, out)
+}
+
+// removeRoute drops every statement of InitRouter that mounts the routes of
+// the handler, such as r.Mount("/orders", deps.OrderHandler.Routes()). Route
+// groups are kept even when they end up empty.
+func removeRoute(pascal string) error {
+	routerPath := routerFilePath()
+	if !fileExists(routerPath) {
+		return nil
+	}
+	src, err := readFile(routerPath)
+	if err != nil {
+		return err
+	}
+	s, err := parseGoSource(routerPath, src)
+	if err != nil {
+		return err
+	}
+
+	fn := s.findFunc("InitRouter")
+	if fn == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return nil
+	}
+	deps := fn.Type.Params.List[0].Names[0].Name
+	field := pascal + "Handler"
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for _, stmt := range block.List {
+			if expr, ok := stmt.(*ast.ExprStmt); ok && mountsHandler(expr, deps, field) {
+				s.remove(stmt)
+			}
+		}
+		return true
+	})
+	if len(s.edits) == 0 {
+		return nil
+	}
+
+	out, err := s.bytes()
+	if err != nil {
+		return err
+	}
+	return writeTracked(routerPath, out)
+}
+
+// mountsHandler reports whether stmt refers to deps.field outside of any
+// function literal, so a whole r.Route(...) group is never matched.
+func mountsHandler(stmt *ast.ExprStmt, deps, field string) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && x.Name == deps && n.Sel.Name == field {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// routeGroupFunc finds the function literal of `router.Route(group, func(...) {...})`
+// among the top-level statements of fn.
+func routeGroupFunc(fn *ast.FuncDecl, router, group string) *ast.FuncLit {
+	for _, stmt := range fn.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Route" {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != router {
+			continue
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			continue
+		}
+		if p, err := strconv.Unquote(lit.Value); err != nil || p != group {
+			continue
+		}
+		if fl, ok := call.Args[1].(*ast.FuncLit); ok {
+			return fl
+		}
+	}
+	return nil
+}
+
+// routerVar returns the variable InitRouter assigns its router to, created
+// with one of the constructors, e.g. chi.NewRouter.
+func routerVar(fn *ast.FuncDecl, constructors []string) string {
+	if assign := routerAssign(fn, constructors); assign != nil {
+		return assign.Lhs[0].(*ast.Ident).Name
+	}
+	return ""
+}
+
+// routerAssign returns the statement of InitRouter creating the router, such
+// as r := chi.NewRouter().
+func routerAssign(fn *ast.FuncDecl, constructors []string) *ast.AssignStmt {
+	for _, stmt := range fn.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || !slices.Contains(constructors, pkg.Name+"."+sel.Sel.Name) {
+			continue
+		}
+		if _, ok := assign.Lhs[0].(*ast.Ident); ok {
+			return assign
+		}
+	}
+	return nil
+}
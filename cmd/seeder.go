@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+var SeederCmd = &cobra.Command{
+	Use:   "seeder [model]",
+	Short: "Create a seeder filling a model's table with fake data",
+	Long: "Create a seeder creating fake rows of a model through its repository, with values picked from the type, gorm tag and name of each field. " +
+		"Foreign keys such as CustomerID reference random customers, so the customers seeder runs first. Run the seeders with `go run ./cmd seed`.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := mustParseName(args[0])
+		moduleName, err := getModuleName()
+		if err != nil {
+			fatalf("❌ Failed to get module name from go.mod: %v", err)
+		}
+
+		pkg, err := readModels()
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		st, ok := pkg.structs[n.Pascal]
+		if !ok {
+			fatalf("❌ There is no %s model in %s, create it with `gostart create model %s --fields ...`", n.Pascal, projectConfig().Layers.Models.Dir, n.Raw)
+		}
+
+		data := newComponentData(moduleName, n)
+		data.Seeder = seederFor(n, st, pkg)
+		data.Seeder.Repository = repositoryCreates(n)
+
+		if err := ensureSeeders(moduleName, slices.ContainsFunc(data.Seeder.Values, func(v types.SeederValue) bool {
+			return strings.Contains(v.Value, "fakePasswordHash")
+		})); err != nil {
+			fatalf("❌ %v", err)
+		}
+
+		path := filepath.Join(seedersDir(), n.Snake+".go")
+		written, err := generateFile("seeder", componentName(n), path, "seeder.tmpl", data)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		if !written {
+			return
+		}
+		fmt.Println("✅ Seeder created at:", path)
+		if !data.Seeder.Repository {
+			fmt.Printf("ℹ️  No %s repository with Create, the seeder writes with GORM\n", n.Human)
+		}
+		for _, field := range data.Seeder.Skipped {
+			fmt.Printf("⚠️  %s has no fake value, set it in %s\n", field, path)
+		}
+	},
+}
+
+// seedersDir holds the seeders, in their own package next to the migrations.
+func seedersDir() string {
+	return filepath.Join(projectConfig().Layers.Database.Dir, "seeders")
+}
+
+// ensureSeeders generates the seeders package and the seed command of
+// projects created before them, plus the password helper when needed.
+func ensureSeeders(moduleName string, password bool) error {
+	files := []struct{ path, tmpl string }{
+		{filepath.Join(seedersDir(), "seeders.go"), "seeders.tmpl"},
+		{filepath.Join(seedersDir(), "fake.go"), "seeder_fake.tmpl"},
+		{"cmd/seed.go", "seed_cmd.tmpl"},
+	}
+	if password {
+		files = append(files, struct{ path, tmpl string }{filepath.Join(seedersDir(), "password.go"), "seeder_password.tmpl"})
+		if err := requireModules("golang.org/x/crypto"); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+	}
+
+	data := newTemplateData(moduleName)
+	for _, f := range files {
+		if fileExists(f.path) {
+			continue
+		}
+		written, err := generateFile("seeder", "seeders", f.path, f.tmpl, data)
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Println("✅ Created:", f.path)
+		}
+	}
+
+	if src, err := readFile("cmd/main.go"); err == nil && !strings.Contains(string(src), `"seed"`) {
+		fmt.Println(`ℹ️  Call seed(cfg, logger, os.Args[2:]) in cmd/main.go when os.Args[1] is "seed" to run the seeders`)
+	}
+	return nil
+}
+
+// seederFor picks a fake value for each field of the model struct.
+func seederFor(n types.Name, st *ast.StructType, pkg *modelPackage) types.Seeder {
+	seeder := types.Seeder{Name: n.PluralSnake}
+	modelsPkg := projectConfig().Layers.Models.Package
+
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+		gorm := gormSettings(tag.Get("gorm"))
+		if _, ignored := gorm["-"]; ignored {
+			continue
+		}
+
+		typ, nullable := field.Type, false
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ, nullable = star.X, true
+		}
+		typeName := gotypes.ExprString(typ)
+
+		for _, id := range field.Names {
+			if !id.IsExported() || slices.Contains([]string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"}, id.Name) {
+				continue
+			}
+			// Associations are filled through their foreign key
+			if _, ok := pkg.structs[strings.TrimPrefix(typeName, "[]")]; ok {
+				continue
+			}
+
+			value := ""
+			if ref, ok := strings.CutSuffix(id.Name, "ID"); ok && ref != "" && isInteger(typeName) {
+				if _, ok := pkg.structs[ref]; ok && ref != n.Pascal {
+					v := camelWords(mustParseName(ref).Words) + "ID"
+					seeder.References = append(seeder.References, types.SeederReference{Var: v, Model: ref})
+					seeder.DependsOn = append(seeder.DependsOn, mustParseName(ref).PluralSnake)
+					value = v
+					if typeName != "uint" {
+						value = typeName + "(" + v + ")"
+					}
+				}
+			}
+			if value == "" {
+				value = fakeValue(n.Pascal+id.Name, mustParseName(id.Name).Words, typeName, gorm, pkg.consts, modelsPkg)
+			}
+			if value == "" {
+				seeder.Skipped = append(seeder.Skipped, id.Name+" "+gotypes.ExprString(field.Type))
+				continue
+			}
+			if nullable {
+				value = "fakeMaybe(" + value + ")"
+			}
+			seeder.Values = append(seeder.Values, types.SeederValue{Field: id.Name, Value: value})
+		}
+	}
+	return seeder
+}
+
+// fakeValue returns the Go expression of a fake value for a field of type
+// typ, or "" when there is none. Enum fields take one of the constants
+// prefixed with the model and field names, e.g. OrderStatusPaid.
+func fakeValue(constPrefix string, words []string, typ string, gorm map[string]string, consts []string, modelsPkg string) string {
+	name := strings.Join(words, "_")
+	last := words[len(words)-1]
+	columnType := strings.ToLower(gorm["type"])
+
+	switch {
+	case typ == "string":
+		var values []string
+		for _, c := range consts {
+			if rest, ok := strings.CutPrefix(c, constPrefix); ok && rest != "" {
+				values = append(values, modelsPkg+"."+c)
+			}
+		}
+		if len(values) > 0 {
+			return "fakePick(" + strings.Join(values, ", ") + ")"
+		}
+		if last == "uuid" || columnType == "uuid" || columnType == "char(36)" {
+			return "fakeUUID()"
+		}
+		if last == "password" {
+			return "fakePasswordHash()"
+		}
+
+		value := "fakeWord()"
+		switch {
+		case last == "email":
+			value = "fakeEmail()"
+		case name == "first_name":
+			value = "fakeFirstName()"
+		case name == "last_name" || last == "surname":
+			value = "fakeLastName()"
+		case last == "name":
+			value = "fakeName()"
+		case last == "phone" || last == "mobile":
+			value = "fakePhone()"
+		case slices.Contains([]string{"url", "website", "link", "avatar", "image"}, last):
+			value = "fakeURL()"
+		case slices.Contains([]string{"title", "subject", "label"}, last):
+			value = "fakeTitle()"
+		case columnType == "text" || slices.Contains([]string{"description", "note", "notes", "body", "content", "bio", "comment", "summary", "message"}, last):
+			value = "fakeSentence()"
+		case slices.Contains([]string{"number", "code", "sku", "reference", "slug", "token"}, last):
+			value = "fakeCode()"
+		}
+
+		size := columnSize(gorm)
+		// Emails carry a random part already, a suffix would break them
+		if isUnique(gorm) && value != "fakeEmail()" {
+			return "fakeUnique(" + value + ", " + strconv.Itoa(size) + ")"
+		}
+		if size > 0 {
+			return "fakeFit(" + value + ", " + strconv.Itoa(size) + ")"
+		}
+		return value
+	case isInteger(typ):
+		lo, hi := "1", "1000"
+		switch last {
+		case "quantity", "qty", "count", "stock":
+			hi = "100"
+		case "age":
+			lo, hi = "18", "80"
+		case "year":
+			lo, hi = "2000", "2030"
+		}
+		return "fakeInt[" + typ + "](" + lo + ", " + hi + ")"
+	case typ == "float64":
+		return "fakeDecimal(1, 1000)"
+	case typ == "float32":
+		return "float32(fakeDecimal(1, 1000))"
+	case typ == "bool":
+		return "fakeBool()"
+	case typ == "time.Time":
+		if columnType == "date" {
+			return "fakeDate()"
+		}
+		return "fakeTime()"
+	}
+	return ""
+}
+
+// gormSettings parses a gorm tag such as "type:varchar(100);uniqueIndex"
+// into lower-case keys and their values.
+func gormSettings(tag string) map[string]string {
+	settings := make(map[string]string)
+	for _, part := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if key != "" {
+			settings[strings.ToLower(key)] = value
+		}
+	}
+	return settings
+}
+
+// columnSize returns the length limit of a string column, 0 when unlimited.
+func columnSize(gorm map[string]string) int {
+	if size, err := strconv.Atoi(gorm["size"]); err == nil {
+		return size
+	}
+	columnType := strings.ToLower(gorm["type"])
+	for _, prefix := range []string{"varchar(", "char(", "nvarchar("} {
+		if rest, ok := strings.CutPrefix(columnType, prefix); ok {
+			if size, err := strconv.Atoi(strings.TrimSuffix(rest, ")")); err == nil {
+				return size
+			}
+		}
+	}
+	return 0
+}
+
+func isUnique(gorm map[string]string) bool {
+	_, unique := gorm["unique"]
+	_, uniqueIndex := gorm["uniqueindex"]
+	return unique || uniqueIndex || strings.Contains(strings.ToLower(gorm["index"]), "unique")
+}
+
+func isInteger(typ string) bool {
+	return slices.Contains([]string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64"}, typ)
+}
+
+// repositoryCreates reports whether the repository of model n declares
+// Create and its New<Model>Repository constructor, as the CRUD ones do.
+func repositoryCreates(n types.Name) bool {
+	dir := filepath.Join(projectConfig().Layers.Repositories.Dir, n.Dir)
+	names, err := listFiles(dir)
+	if err != nil {
+		return false
+	}
+
+	create, constructor := false, false
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		src, err := readFile(path)
+		if err != nil {
+			continue
+		}
+		s, err := parseGoSource(path, src)
+		if err != nil {
+			continue
+		}
+		if s.findFunc("New"+n.Pascal+"Repository") != nil {
+			constructor = true
+		}
+		ast.Inspect(s.file, func(node ast.Node) bool {
+			ts, ok := node.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != n.Pascal+"Repository" {
+				return true
+			}
+			if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+				for _, m := range iface.Methods.List {
+					if len(m.Names) == 1 && m.Names[0].Name == "Create" {
+						create = true
+					}
+				}
+			}
+			return false
+		})
+	}
+	return create && constructor
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/faidfadjri/gostart/cmd/types"
+	"github.com/spf13/cobra"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// projectTemplateDir holds per-project template overrides.
+const projectTemplateDir = ".gostart/templates"
+
+// userTemplateDir holds per-user template overrides, e.g. ~/.config/gostart/templates.
+func userTemplateDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gostart", "templates"), nil
+}
+
+// resolveTemplate finds the template called name (e.g. "handler.tmpl"). The
+// project directory wins over the user directory, which wins over the
+// embedded default. It returns the source and where it came from.
+func resolveTemplate(name string) (string, string, error) {
+	dirs := []string{projectTemplateDir}
+	if dir, err := userTemplateDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	for _, dir := range dirs {
+		p := filepath.Join(dir, name)
+		content, err := os.ReadFile(p)
+		if err == nil {
+			return string(content), p, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("failed to read template %s: %w", p, err)
+		}
+	}
+
+	content, err := templateFS.ReadFile(path.Join("templates", name))
+	if err != nil {
+		return "", "", fmt.Errorf("unknown template %s", name)
+	}
+	return string(content), "embedded", nil
+}
+
+// parseTemplate resolves and parses the template called name.
+func parseTemplate(name string) (*template.Template, error) {
+	content, _, err := resolveTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(name).Funcs(templateFuncs).Parse(content)
+}
+
+// templateFuncs are available to every template.
+var templateFuncs = template.FuncMap{
+	"plural": pluralize,
+	"quoteJoin": func(values []string) string {
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = strconv.Quote(v)
+		}
+		return strings.Join(quoted, ", ")
+	},
+	"join": strings.Join,
+	"pascal": func(s string) string {
+		return snakeToPascal(strings.ReplaceAll(strings.ToLower(s), "-", "_"))
+	},
+	"hasEnum": func(fields []types.Field) bool {
+		for _, f := range fields {
+			if f.IsEnum() {
+				return true
+			}
+		}
+		return false
+	},
+	"hasRequired": func(fields []types.Field) bool {
+		for _, f := range fields {
+			if strings.HasPrefix(f.ValidateTag(false), "required") {
+				return true
+			}
+		}
+		return false
+	},
+	"hasTime": func(fields []types.Field) bool {
+		for _, f := range fields {
+			if f.IsTime() {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// executeTemplate renders the template called name. Go output is gofmt-ed
+// when it parses, so user templates do not have to be perfectly aligned.
+func executeTemplate(name string, data types.TemplateData) ([]byte, error) {
+	tmpl, err := parseTemplate(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+
+	if formatted, err := format.Source(buf.Bytes()); err == nil {
+		return formatted, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// generateFile renders tmpl to path, following the conflict policy, and
+// records it in the manifest under the kind/name component. It reports
+// whether the file was written.
+func generateFile(kind, name, path, tmpl string, data types.TemplateData) (bool, error) {
+	if err := checkOwner(kind, name, path); err != nil {
+		return false, err
+	}
+	content, err := executeTemplate(tmpl, data)
+	if err != nil {
+		return false, err
+	}
+	if err := makeDir(filepath.Dir(path)); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	written, err := writeGenerated(path, content)
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if written {
+		recordGenerated(kind, name, path, tmpl, content)
+	}
+	return written, nil
+}
+
+func embeddedTemplateNames() []string {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		fatalf("❌ Failed to list embedded templates: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+var TemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List and eject the templates used by the generators",
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and where each one is loaded from",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, name := range embeddedTemplateNames() {
+			_, source, err := resolveTemplate(name)
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			fmt.Printf("📄 %-28s %s\n", name, source)
+		}
+	},
+}
+
+var ejectToUserDir bool
+
+var templatesEjectCmd = &cobra.Command{
+	Use:   "eject [name]",
+	Short: "Copy embedded templates into .gostart/templates for editing",
+	Long:  "Copy one embedded template (e.g. handler or handler.tmpl), or all of them, into the project template directory, or the user directory with --user.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		destDir := projectTemplateDir
+		if ejectToUserDir {
+			dir, err := userTemplateDir()
+			if err != nil {
+				fatalf("❌ Failed to locate user config directory: %v", err)
+			}
+			destDir = dir
+		}
+
+		names := embeddedTemplateNames()
+		if len(args) > 0 {
+			name := args[0]
+			if !strings.HasSuffix(name, ".tmpl") {
+				name += ".tmpl"
+			}
+			if _, err := templateFS.ReadFile(path.Join("templates", name)); err != nil {
+				fatalf("❌ Unknown template %q, run `gostart templates list` to see them", args[0])
+			}
+			names = []string{name}
+		}
+
+		if err := makeDir(destDir); err != nil {
+			fatalf("❌ Failed to create %s: %v", destDir, err)
+		}
+
+		for _, name := range names {
+			content, _ := templateFS.ReadFile(path.Join("templates", name))
+			outputPath := filepath.Join(destDir, name)
+			written, err := writeGenerated(outputPath, content)
+			if err != nil {
+				fatalf("❌ Failed to eject %s: %v", name, err)
+			}
+			if written {
+				fmt.Println("✅ Ejected template:", outputPath)
+			}
+		}
+	},
+}
+
+func init() {
+	templatesEjectCmd.Flags().BoolVar(&ejectToUserDir, "user", false, "Eject into the user template directory instead of the project")
+	TemplatesCmd.AddCommand(templatesListCmd)
+	TemplatesCmd.AddCommand(templatesEjectCmd)
+}
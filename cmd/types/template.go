@@ -1,7 +1,208 @@
 package types
 
+import (
+	"strconv"
+	"strings"
+)
+
 type TemplateData struct {
-	ServiceName      string
-	ServiceNameLower string
+	ServiceName      string // PascalCase component name, e.g. OrderItem
+	ServiceNameLower string // package name of the component, e.g. orderitem
+	Name             Name
 	ModuleName       string
+	Layers           Layers
+	Fields           []Field
+	Database         Database
+	Router           string   // chi, stdlib, echo, gin or fiber
+	ErrorFormat      string   // body of error responses: envelope, problem or jsonapi
+	Models           []string // model type names, e.g. [Order User]
+	Seeder           Seeder
+	Mock             Mock
+}
+
+// Name is a component name in every form the templates need.
+type Name struct {
+	Raw          string   // as given on the command line, e.g. shop/order_item
+	Words        []string // e.g. [order item]
+	Pascal       string   // e.g. OrderItem
+	Camel        string   // e.g. orderItem
+	Snake        string   // e.g. order_item
+	Kebab        string   // e.g. order-item
+	Human        string   // e.g. order item
+	PluralPascal string   // e.g. OrderItems
+	PluralCamel  string   // e.g. orderItems
+	PluralSnake  string   // e.g. order_items
+	PluralKebab  string   // e.g. order-items
+	PluralHuman  string   // e.g. order items
+	Package      string   // package-safe name, e.g. orderitem
+	Parents      []string // package-safe parent directories, e.g. [shop]
+	Dir          string   // nested package directory, e.g. shop/orderitem
+}
+
+// Database is the database driver of the generated project.
+type Database struct {
+	Driver  string // postgres, mysql, sqlite or sqlserver
+	Import  string // GORM driver import path, e.g. gorm.io/driver/postgres
+	Package string // GORM driver package name, e.g. postgres
+	Port    string // default port, empty for sqlite
+	User    string // default user
+	Image   string // docker image of the database service, empty for sqlite
+}
+
+// Seeder describes the seeder of a model, read from its struct.
+type Seeder struct {
+	Name       string            // seeder name, e.g. orders
+	DependsOn  []string          // seeders of the referenced models, e.g. [customers]
+	Repository bool              // the model repository has Create, else GORM is used
+	References []SeederReference // rows picked for the foreign keys
+	Values     []SeederValue     // fake value of each field
+	Skipped    []string          // fields left to their zero value, e.g. Meta datatypes.JSON
+}
+
+// SeederReference is a foreign key filled with the id of an existing row.
+type SeederReference struct {
+	Var   string // e.g. customerID
+	Model string // e.g. Customer
+}
+
+// SeederValue is a field and the Go expression of its fake value.
+type SeederValue struct {
+	Field string // e.g. Total
+	Value string // e.g. fakeDecimal(1, 1000)
+}
+
+// Mock describes the mock of an interface, read from its declaration.
+type Mock struct {
+	Name       string       // mock type name, e.g. OrderRepository
+	Interface  string       // qualified interface, e.g. orderrepo.OrderRepository
+	StdImports []string     // standard library import specs besides sync, e.g. "context"
+	Imports    []string     // other import specs, e.g. orderrepo "example.com/app/..."
+	Methods    []MockMethod // methods of the interface
+}
+
+// MockMethod is a method of a mocked interface.
+type MockMethod struct {
+	Name    string // e.g. FindByID
+	Params  string // e.g. ctx context.Context, id uint
+	Args    string // arguments forwarding the params, e.g. ctx, id
+	Results string // e.g. (*models.Order, error), empty without results
+}
+
+// Layer is a package of the generated project.
+type Layer struct {
+	Dir     string // e.g. internal/app/usecases
+	Import  string // e.g. github.com/acme/shop/internal/app/usecases
+	Package string // e.g. usecases
+}
+
+// Layers lists every package the templates refer to.
+type Layers struct {
+	Usecases     Layer
+	Repositories Layer
+	Handlers     Layer
+	Bootstrap    Layer
+	Routes       Layer
+	Database     Layer
+	Models       Layer
+	Config       Layer
+	Request      Layer
+	Response     Layer
+	Middlewares  Layer
+	Services     Layer
+	Logging      Layer
+	Mocks        Layer
+	Pagination   Layer
+	Errors       Layer
+	Docs         Layer
+}
+
+// Field is a model field parsed from a --fields specification.
+type Field struct {
+	Name       string   // snake_case name from the spec, e.g. customer_id
+	GoName     string   // exported Go name, e.g. CustomerID
+	JSONName   string   // JSON key, e.g. customer_id
+	Type       string   // spec type, e.g. uint, decimal, enum
+	GoType     string   // Go type, e.g. uint, *string, time.Time
+	BaseType   string   // Go type without the pointer, e.g. string
+	GormTag    string   // gorm struct tag value
+	Nullable   bool     // declared with a trailing ?
+	ForeignKey bool     // declared with the fk modifier
+	EnumValues []string // allowed values of an enum field
+}
+
+// IsEnum reports whether the field only accepts EnumValues.
+func (f Field) IsEnum() bool {
+	return len(f.EnumValues) > 0
+}
+
+// IsTime reports whether the field needs the time package.
+func (f Field) IsTime() bool {
+	return f.BaseType == "time.Time"
+}
+
+// IsUnique reports whether the field was declared with the unique modifier.
+func (f Field) IsUnique() bool {
+	return strings.Contains(f.GormTag, "uniqueIndex")
+}
+
+// ValidateTag returns the validate struct tag rules of the field in the
+// create request, or in the update request when update is set, where every
+// field is optional.
+func (f Field) ValidateTag(update bool) string {
+	var rules []string
+	switch {
+	case update && !f.Nullable && f.BaseType == "string":
+		rules = append(rules, "notblank")
+	case update && f.ForeignKey:
+		rules = append(rules, "min=1")
+	case !update && !f.Nullable && (f.BaseType == "string" || f.ForeignKey):
+		rules = append(rules, "required")
+	}
+	switch {
+	case f.IsEnum():
+		rules = append(rules, "oneof="+strings.Join(f.EnumValues, " "))
+	case f.Type == "uuid":
+		rules = append(rules, "uuid")
+	case f.BaseType == "string" && (f.Name == "email" || strings.HasSuffix(f.Name, "_email")):
+		rules = append(rules, "email")
+	}
+	return strings.Join(rules, ",")
+}
+
+// Example returns a Go literal of a valid value of the field, without the
+// pointer of nullable fields, for the generated tests.
+func (f Field) Example() string {
+	switch {
+	case f.IsEnum():
+		return strconv.Quote(f.EnumValues[0])
+	case f.Type == "uuid":
+		return `"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	case f.BaseType == "string" && (f.Name == "email" || strings.HasSuffix(f.Name, "_email")):
+		return strconv.Quote(strings.ReplaceAll(f.Name, "_", ".") + "@example.com")
+	case f.BaseType == "string":
+		return strconv.Quote("example " + strings.ReplaceAll(f.Name, "_", " "))
+	case f.BaseType == "bool":
+		return "true"
+	case f.BaseType == "float64":
+		return "9.99"
+	case f.IsTime():
+		return "time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)"
+	}
+	return "1"
+}
+
+// Vary returns a statement changing the field of the struct v by the index i,
+// so that rows created in a loop keep unique fields distinct. It is "" for
+// bools, which cannot vary enough.
+func (f Field) Vary(v, i string) string {
+	field := v + "." + f.GoName
+	switch {
+	case f.BaseType == "bool":
+		return ""
+	case f.BaseType == "string":
+		return field + " = string(rune('a'+" + i + ")) + " + field
+	case f.IsTime():
+		return field + " = " + field + ".AddDate(0, 0, " + i + ")"
+	}
+	return field + " += " + f.BaseType + "(" + i + ")"
 }
@@ -1,92 +1,91 @@
 package cmd
 
 import (
-	"bytes"
-	_ "embed"
 	"fmt"
 	"go/format"
 	"log"
-	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/faidfadjri/gostart/cmd/types"
 	"github.com/spf13/cobra"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-//go:embed templates/usecase.tmpl
-var usecaseTemplate string
-
-//go:embed templates/usecase_interface.tmpl
-var interfaceTemplate string
-
 var UsecaseCmd = &cobra.Command{
 	Use:   "usecase [name]",
 	Short: "Create a new usecase",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := strings.ToLower(args[0])
-		caser := cases.Title(language.English)
-		serviceName := caser.String(name)
+		n := mustParseComponentName(args[0])
 
-		destDir := fmt.Sprintf("internal/app/usecases/%s", name)
-		if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
-			log.Fatalf("❌ Failed to create usecases directory: %v", err)
+		cfg := projectConfig()
+		destDir := filepath.Join(cfg.Layers.Usecases.Dir, filepath.FromSlash(n.Dir))
+		if err := makeDir(destDir); err != nil {
+			fatalf("❌ Failed to create usecases directory: %v", err)
 		}
 
 		moduleName, _ := getModuleName()
 
-		templateData := types.TemplateData{
-			ServiceName:      serviceName,
-			ServiceNameLower: name,
-			ModuleName:       moduleName,
+		templateData := newComponentData(moduleName, n)
+		templateData.Fields = templateFields()
+
+		// Field-based generation swaps the stubs for CRUD templates
+		usecaseTmpl, interfaceTmplName := "usecase.tmpl", "usecase_interface.tmpl"
+		if len(templateData.Fields) > 0 {
+			usecaseTmpl, interfaceTmplName = "usecase_crud.tmpl", "usecase_interface_crud.tmpl"
+			ensureAppErrors(templateData)
 		}
 
-		// Parse and write usecase.tmpl
-		tmpl, err := template.New("usecase").Parse(usecaseTemplate)
+		// Render usecase.tmpl
+		content, err := executeTemplate(usecaseTmpl, templateData)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse embedded usecase template: %v", err)
+			fatalf("❌ %v", err)
 		}
-		var buf bytes.Buffer
-		err = tmpl.Execute(&buf, templateData)
+		outputPath := filepath.Join(destDir, fileName(cfg.Files.Usecase, n.Snake))
+		written, err := writeGenerated(outputPath, content)
 		if err != nil {
-			log.Fatalf("❌ Failed to execute usecase template: %v", err)
+			fatalf("❌ Failed to write usecase file: %v", err)
 		}
-		outputPath := filepath.Join(destDir, fmt.Sprintf("%s_usecase.go", name))
-		err = os.WriteFile(outputPath, buf.Bytes(), 0644)
-		if err != nil {
-			log.Fatalf("❌ Failed to write usecase file: %v", err)
+		if written {
+			recordGenerated("usecase", componentName(n), outputPath, usecaseTmpl, content)
+			fmt.Println("✅ Usecase created at:", outputPath)
 		}
-		fmt.Println("✅ Usecase created at:", outputPath)
 
-		// Parse and write usecase_interface.tmpl
-		interfaceTmpl, err := template.New("usecase_interface").Parse(interfaceTemplate)
+		// Render usecase_interface.tmpl
+		interfaceContent, err := executeTemplate(interfaceTmplName, templateData)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse embedded interface template: %v", err)
+			fatalf("❌ %v", err)
 		}
-		var interfaceBuf bytes.Buffer
-		err = interfaceTmpl.Execute(&interfaceBuf, templateData)
+		interfacePath := filepath.Join(destDir, fileName(cfg.Files.Interface, n.Snake))
+		written, err = writeGenerated(interfacePath, interfaceContent)
 		if err != nil {
-			log.Fatalf("❌ Failed to execute interface template: %v", err)
+			fatalf("❌ Failed to write interface.go: %v", err)
 		}
-		interfacePath := filepath.Join(destDir, "interface.go")
-		err = os.WriteFile(interfacePath, interfaceBuf.Bytes(), 0644)
-		if err != nil {
-			log.Fatalf("❌ Failed to write interface.go: %v", err)
+		if written {
+			recordGenerated("usecase", componentName(n), interfacePath, interfaceTmplName, interfaceContent)
+			fmt.Println("✅ Interface created at:", interfacePath)
+		}
+
+		testTmpl := "usecase_test.tmpl"
+		if len(templateData.Fields) > 0 {
+			testTmpl = "usecase_crud_test.tmpl"
+		}
+		if err := generateTest("usecase", n, filepath.Join(destDir, testFileName(cfg.Files.Usecase, n.Snake)), testTmpl, templateData); err != nil {
+			fatalf("❌ %v", err)
+		}
+		if err := generateMock("usecase", n, interfacePath, n.Pascal+"Usecase", templateData.Layers.Usecases.Import+"/"+n.Dir, n.Package+"usecase", fileName(cfg.Files.Usecase, n.Snake)); err != nil {
+			fatalf("❌ Failed to write the usecase mock: %v", err)
 		}
-		fmt.Println("✅ Interface created at:", interfacePath)
 
 		// Update usecases.go
-		err = createOrUpdateUsecasesIndex(serviceName, name)
+		err = createOrUpdateUsecasesIndex(n.Pascal, n.Dir)
 		if err != nil {
-			log.Fatalf("❌ Failed to create/update usecases.go: %v", err)
+			fatalf("❌ Failed to create/update usecases.go: %v", err)
 		}
-		fmt.Println("✅ Usecases index updated at: internal/app/usecases/usecases.go")
+		fmt.Println("✅ Usecases index updated at:", usecaseIndexPath())
 	},
 }
 
@@ -96,58 +95,99 @@ func createOrUpdateUsecasesIndex(serviceName, name string) error {
 		return fmt.Errorf("failed to get module name: %w", err)
 	}
 
-	usecasesPath := "internal/app/usecases/usecases.go"
+	usecasesPath := usecaseIndexPath()
 
-	if _, err := os.Stat(usecasesPath); os.IsNotExist(err) {
+	if !fileExists(usecasesPath) {
 		return createNewUsecasesIndex(usecasesPath, moduleName, serviceName, name)
 	}
 	return updateExistingUsecasesIndex(usecasesPath, moduleName, serviceName, name)
 }
 
-func createNewUsecasesIndex(path, moduleName, serviceName, name string) error {
-	content := fmt.Sprintf(`package usecases
-
-import "%s/internal/app/usecases/%s"
+// checkIndexedName fails when another component already declares the flat
+// aliases of n in the usecases or repositories index, such as UserRoleUsecase
+// declared by admin/user_role for user_role. It runs before any file is
+// written, leaving no orphan package behind.
+func checkIndexedName(n types.Name) error {
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
+	if content, err := readFile(usecaseIndexPath()); err == nil {
+		entries, err := parseExistingEntries(string(content), moduleName)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.ServiceName == n.Pascal && entry.Name != n.Dir {
+				return fmt.Errorf("%sUsecase is already declared by %s, choose another name", n.Pascal, entry.Name)
+			}
+		}
+	}
+	if content, err := readFile(repositoryIndexPath()); err == nil {
+		entries, err := parseExistingRepositoryEntries(string(content), moduleName)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.ServiceName == n.Pascal && entry.Name != n.Dir {
+				return fmt.Errorf("%sRepository is already declared by %s, choose another name", n.Pascal, entry.Name)
+			}
+		}
+	}
+	return nil
+}
 
-type %sUsecase = %s.%sUsecase
+// usecaseIndexPath is the file aliasing every usecase, e.g. internal/app/usecases/usecases.go.
+func usecaseIndexPath() string {
+	layer := projectConfig().Layers.Usecases
+	return filepath.Join(layer.Dir, layer.Package+".go")
+}
 
-var (
-	New%sUsecase = %s.New%sUsecase
-)
-`, moduleName, name, serviceName, name, serviceName, serviceName, name, serviceName)
+func createNewUsecasesIndex(path, moduleName, serviceName, name string) error {
+	content := generateCleanUsecasesFile([]UsecaseEntry{{
+		Name:        name,
+		ServiceName: serviceName,
+		ModuleName:  moduleName,
+	}})
 
 	formattedContent, err := format.Source([]byte(content))
 	if err != nil {
 		formattedContent = []byte(content)
 	}
-	return os.WriteFile(path, formattedContent, 0644)
+	return writeFile(path, formattedContent)
 }
 
 // UsecaseEntry represents a single usecase entry
 type UsecaseEntry struct {
-	Name        string // e.g., "user"
+	Name        string // e.g., "user" or "shop/orderitem"
 	ServiceName string // e.g., "User"
 	ModuleName  string
 }
 
 func updateExistingUsecasesIndex(path, moduleName, serviceName, name string) error {
-	content, err := os.ReadFile(path)
+	content, err := readFile(path)
 	if err != nil {
 		return err
 	}
 	contentStr := string(content)
 
-	// Skip if already exists
-	if strings.Contains(contentStr, fmt.Sprintf("New%sUsecase", serviceName)) {
-		return nil
-	}
-
 	// Parse existing entries
 	entries, err := parseExistingEntries(contentStr, moduleName)
 	if err != nil {
 		return err
 	}
 
+	// Skip if already exists, the aliases are flat so names must be unique
+	for _, entry := range entries {
+		if entry.ServiceName != serviceName {
+			continue
+		}
+		if entry.Name != name {
+			return fmt.Errorf("%sUsecase is already declared by %s, choose another name", serviceName, entry.Name)
+		}
+		return nil
+	}
+
 	// Add new entry
 	entries = append(entries, UsecaseEntry{
 		Name:        name,
@@ -170,46 +210,82 @@ func updateExistingUsecasesIndex(path, moduleName, serviceName, name string) err
 		formatted = []byte(newContent)
 	}
 
-	return os.WriteFile(path, formatted, 0644)
+	return writeFile(path, formatted)
+}
+
+// removeFromUsecasesIndex drops the usecase in package directory name from the
+// usecases index, deleting the index once nothing is left in it.
+func removeFromUsecasesIndex(name string) error {
+	moduleName, err := getModuleName()
+	if err != nil {
+		return fmt.Errorf("failed to get module name: %w", err)
+	}
+
+	indexPath := usecaseIndexPath()
+	if !fileExists(indexPath) {
+		return nil
+	}
+	content, err := readFile(indexPath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := parseExistingEntries(string(content), moduleName)
+	if err != nil {
+		return err
+	}
+	var kept []UsecaseEntry
+	for _, entry := range entries {
+		if entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return removeFile(indexPath)
+	}
+
+	newContent := generateCleanUsecasesFile(kept)
+	formatted, err := format.Source([]byte(newContent))
+	if err != nil {
+		formatted = []byte(newContent)
+	}
+	return writeFile(indexPath, formatted)
 }
 
 func parseExistingEntries(content, moduleName string) ([]UsecaseEntry, error) {
 	var entries []UsecaseEntry
 
-	// Extract imports
-	importRegex := regexp.MustCompile(`"` + regexp.QuoteMeta(moduleName) + `/internal/app/usecases/([^"]+)"`)
-	importMatches := importRegex.FindAllStringSubmatch(content, -1)
+	// Extract imports, keyed by the identifier the file uses for them
+	layer := projectConfig().Layers.Usecases
+	importRegex := regexp.MustCompile(`(?m)^\s*(?:(\w+)\s+)?"` + regexp.QuoteMeta(moduleName+"/"+layer.Dir) + `/([^"]+)"`)
+	packages := make(map[string]string) // identifier -> name, e.g. "orderitem" -> "shop/orderitem"
+	for _, match := range importRegex.FindAllStringSubmatch(content, -1) {
+		ident := match[1]
+		if ident == "" || ident == "import" {
+			ident = path.Base(match[2])
+		}
+		packages[ident] = match[2]
+	}
 
-	// Extract type aliases
+	// Extract type aliases, only from type declarations since the var block
+	// repeats the pattern for the constructors
+	typeDecls := typeDeclarations(content)
 	typeRegex := regexp.MustCompile(`(\w+)Usecase\s*=\s*(\w+)\.(\w+)Usecase`)
-	typeMatches := typeRegex.FindAllStringSubmatch(content, -1)
+	typeMatches := typeRegex.FindAllStringSubmatch(typeDecls, -1)
 
-	// Create a map for easier lookup
-	typeMap := make(map[string]string) // serviceName -> name
+	// Build entries based on type aliases pointing at an imported package
 	for _, match := range typeMatches {
-		if len(match) >= 4 {
-			serviceName := match[1] // e.g., "User"
-			packageName := match[2] // e.g., "user"
-			typeMap[serviceName] = packageName
-		}
-	}
-
-	// Build entries based on imports
-	for _, match := range importMatches {
-		if len(match) >= 2 {
-			packageName := match[1] // e.g., "user"
-			// Convert to service name (capitalize first letter)
-			caser := cases.Title(language.English)
-			serviceName := caser.String(packageName)
-
-			// Verify this entry exists in types
-			if _, exists := typeMap[serviceName]; exists {
-				entries = append(entries, UsecaseEntry{
-					Name:        packageName,
-					ServiceName: serviceName,
-					ModuleName:  moduleName,
-				})
-			}
+		serviceName := match[1] // e.g., "OrderItem"
+		packageName := match[2] // e.g., "orderitem"
+		if name, exists := packages[packageName]; exists && match[3] == serviceName {
+			entries = append(entries, UsecaseEntry{
+				Name:        name,
+				ServiceName: serviceName,
+				ModuleName:  moduleName,
+			})
 		}
 	}
 
@@ -219,13 +295,14 @@ func parseExistingEntries(content, moduleName string) ([]UsecaseEntry, error) {
 func generateCleanUsecasesFile(entries []UsecaseEntry) string {
 	var buf strings.Builder
 
-	buf.WriteString("package usecases\n\n")
+	layer := projectConfig().Layers.Usecases
+	buf.WriteString("package " + layer.Package + "\n\n")
 
 	// Generate imports
 	if len(entries) > 0 {
 		buf.WriteString("import (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\t\"%s/internal/app/usecases/%s\"\n", entry.ModuleName, entry.Name))
+			buf.WriteString(fmt.Sprintf("\t\"%s/%s/%s\"\n", entry.ModuleName, layer.Dir, entry.Name))
 		}
 		buf.WriteString(")\n\n")
 	}
@@ -234,7 +311,7 @@ func generateCleanUsecasesFile(entries []UsecaseEntry) string {
 	if len(entries) > 0 {
 		buf.WriteString("type (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\t%sUsecase = %s.%sUsecase\n", entry.ServiceName, entry.Name, entry.ServiceName))
+			buf.WriteString(fmt.Sprintf("\t%sUsecase = %s.%sUsecase\n", entry.ServiceName, path.Base(entry.Name), entry.ServiceName))
 		}
 		buf.WriteString(")\n\n")
 	}
@@ -243,10 +320,23 @@ func generateCleanUsecasesFile(entries []UsecaseEntry) string {
 	if len(entries) > 0 {
 		buf.WriteString("var (\n")
 		for _, entry := range entries {
-			buf.WriteString(fmt.Sprintf("\tNew%sUsecase = %s.New%sUsecase\n", entry.ServiceName, entry.Name, entry.ServiceName))
+			buf.WriteString(fmt.Sprintf("\tNew%sUsecase = %s.New%sUsecase\n", entry.ServiceName, path.Base(entry.Name), entry.ServiceName))
 		}
 		buf.WriteString(")\n")
 	}
 
 	return buf.String()
 }
+
+// typeDeclarations returns the text of every type declaration in an index
+// file, both grouped and single-line ones.
+func typeDeclarations(content string) string {
+	var buf strings.Builder
+	for _, match := range regexp.MustCompile(`(?s)\btype\s*\((.*?)\n\)`).FindAllStringSubmatch(content, -1) {
+		buf.WriteString(match[1] + "\n")
+	}
+	for _, match := range regexp.MustCompile(`(?m)^type\s+(\w+\s*=.*)$`).FindAllStringSubmatch(content, -1) {
+		buf.WriteString(match[1] + "\n")
+	}
+	return buf.String()
+}
@@ -4,10 +4,13 @@ go 1.24.0
 
 require (
 	github.com/spf13/cobra v1.9.1
-	golang.org/x/text v0.27.0
+	golang.org/x/mod v0.32.0
+	golang.org/x/tools v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/sync v0.19.0 // indirect
 )
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/faidfadjri/gostart/cmd"
 	"github.com/spf13/cobra"
@@ -10,12 +11,29 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "gostart",
 	Short: "gostart is a mini Go framework generator",
+	PersistentPreRun: func(c *cobra.Command, args []string) {
+		cmd.ApplyConfigDefaults(c)
+	},
+	PersistentPostRun: func(c *cobra.Command, args []string) {
+		cmd.Finish(os.Stdout)
+	},
 }
 
 func main() {
+	rootCmd.PersistentFlags().BoolVar(&cmd.DryRun, "dry-run", false, "Print planned changes as a unified diff without writing anything")
+	rootCmd.PersistentFlags().BoolVar(&cmd.Force, "force", false, "Overwrite existing files")
+	rootCmd.PersistentFlags().BoolVar(&cmd.SkipExisting, "skip-existing", false, "Keep existing files and only create missing ones")
+	rootCmd.PersistentFlags().BoolVarP(&cmd.Interactive, "interactive", "i", false, "Ask whether to keep, overwrite, diff or merge each existing file")
+	rootCmd.MarkFlagsMutuallyExclusive("force", "skip-existing", "interactive")
+
 	rootCmd.AddCommand(cmd.CreateCmd)
 	rootCmd.AddCommand(cmd.InitCmd)
+	rootCmd.AddCommand(cmd.AddCmd)
 	rootCmd.AddCommand(cmd.DockerCmd)
+	rootCmd.AddCommand(cmd.TemplatesCmd)
+	rootCmd.AddCommand(cmd.DestroyCmd)
+	rootCmd.AddCommand(cmd.ListCmd)
+	rootCmd.AddCommand(cmd.OpenAPICmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)